@@ -0,0 +1,73 @@
+// Command backup archives the survey store into a single file or restores it
+// from one. Encrypted stores are archived as they are; keep data/key.json
+// with the archive to be able to read it after a restore.
+//
+//	backup -out surveys.tar.gz
+//	backup -restore surveys.tar.gz
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AVZotov/draft-survey/internal/storage"
+)
+
+func main() {
+	data := flag.String("data", "data", "data directory")
+	out := flag.String("out", "", "write an archive of the store to this file")
+	restore := flag.String("restore", "", "restore the store from this archive")
+	flag.Parse()
+
+	store := storage.JSONStore{
+		Path:       filepath.Join(*data, "surveys"),
+		TempPath:   filepath.Join(*data, "temp"),
+		BackupPath: filepath.Join(*data, "backups"),
+	}
+
+	var err error
+	switch {
+	case *out != "" && *restore == "":
+		err = archive(store, *out)
+	case *restore != "" && *out == "":
+		err = restoreArchive(store, *restore)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "backup:", err)
+		os.Exit(1)
+	}
+}
+
+func archive(store storage.JSONStore, name string) (err error) {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	if err = store.Archive(file); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func restoreArchive(store storage.JSONStore, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = os.MkdirAll(store.Path, 0755); err != nil {
+		return err
+	}
+	return store.RestoreArchive(file)
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
 	"github.com/AVZotov/draft-survey/internal/vessel"
 )
 
@@ -23,11 +24,19 @@ func TotalBallastWater(bwt []types.BallastWaterTank) float64 {
 	return total
 }
 
+func TotalIceAccretion(ia []types.IceAccretion) float64 {
+	var total float64
+	for _, z := range ia {
+		total += round3(z.GetWeight())
+	}
+	return total
+}
+
 func MeanDrafts(m types.Marks) types.MeanDraft {
 	return types.MeanDraft{
-		DraftFwdMean: round3((m.FwdPort.Value + m.FwdStarboard.Value) / 2),
-		DraftMidMean: round3((m.MidPort.Value + m.MidStarboard.Value) / 2),
-		DraftAftMean: round3((m.AftPort.Value + m.AftStarboard.Value) / 2),
+		DraftFwdMean: round3((m.FwdPort.Metres() + m.FwdStarboard.Metres()) / 2),
+		DraftMidMean: round3((m.MidPort.Metres() + m.MidStarboard.Metres()) / 2),
+		DraftAftMean: round3((m.AftPort.Metres() + m.AftStarboard.Metres()) / 2),
 	}
 }
 
@@ -35,16 +44,16 @@ func CalcFullLBPPPCorrections(m types.MeanDraft, v vessel.VesselData) types.PPCo
 	trim := m.DraftAftMean - m.DraftFwdMean
 	var dFwdDir, dMidDir, dAftDir float64
 
-	if dFwdDir = v.DistancePPFwd; v.PPFwdDirection == vessel.PPDirectionAft {
+	if dFwdDir = v.DistancePPFwd.Metres(); v.PPFwdDirection == vessel.PPDirectionAft {
 		dFwdDir *= -1
 	}
-	if dMidDir = v.DistancePPMid; v.PPMidDirection == vessel.PPDirectionAft {
+	if dMidDir = v.DistancePPMid.Metres(); v.PPMidDirection == vessel.PPDirectionAft {
 		dMidDir *= -1
 	}
-	if dAftDir = v.DistancePPAft; v.PPAftDirection == vessel.PPDirectionAft {
+	if dAftDir = v.DistancePPAft.Metres(); v.PPAftDirection == vessel.PPDirectionAft {
 		dAftDir *= -1
 	}
-	lbm := round3(v.LBP - dAftDir + dFwdDir)
+	lbm := round3(v.LBP.Metres() - dAftDir + dFwdDir)
 	return types.PPCorrections{
 		FwdCorrection: round3(dFwdDir * trim / lbm),
 		MidCorrection: round3(dMidDir * trim / lbm),
@@ -55,22 +64,23 @@ func CalcFullLBPPPCorrections(m types.MeanDraft, v vessel.VesselData) types.PPCo
 func CalcHalfLBPPPCorrections(m types.MeanDraft, v vessel.VesselData) types.PPCorrections {
 	var dFwdDir, dMidDir, dAftDir float64
 
-	if dFwdDir = v.DistancePPFwd; v.PPFwdDirection == vessel.PPDirectionAft {
+	if dFwdDir = v.DistancePPFwd.Metres(); v.PPFwdDirection == vessel.PPDirectionAft {
 		dFwdDir *= -1
 	}
-	if dMidDir = v.DistancePPMid; v.PPMidDirection == vessel.PPDirectionAft {
+	if dMidDir = v.DistancePPMid.Metres(); v.PPMidDirection == vessel.PPDirectionAft {
 		dMidDir *= -1
 	}
-	if dAftDir = v.DistancePPAft; v.PPAftDirection == vessel.PPDirectionAft {
+	if dAftDir = v.DistancePPAft.Metres(); v.PPAftDirection == vessel.PPDirectionAft {
 		dAftDir *= -1
 	}
 
-	lbmMidFwd := round3((v.LBP / 2) - dMidDir - dFwdDir)
-	lbmAftMid := round3((v.LBP / 2) - dAftDir - dMidDir)
+	lbmMidFwd := round3((v.LBP.Metres() / 2) - dMidDir - dFwdDir)
+	lbmAftMid := round3((v.LBP.Metres() / 2) - dAftDir - dMidDir)
 
 	fwdCorr := round3(dFwdDir * (m.DraftMidMean - m.DraftFwdMean) / lbmMidFwd)
 	midCorr := round3(dMidDir * (m.DraftMidMean - m.DraftFwdMean) / lbmMidFwd)
-	midWKeel := round3(m.DraftMidMean + midCorr - (v.KeelMid / 1000))
+	_, keelMid, _ := v.KeelThickness()
+	midWKeel := round3(m.DraftMidMean + midCorr - keelMid.Metres())
 	aftCorr := round3(dAftDir * (m.DraftAftMean - midWKeel) / lbmAftMid)
 
 	return types.PPCorrections{
@@ -82,9 +92,10 @@ func CalcHalfLBPPPCorrections(m types.MeanDraft, v vessel.VesselData) types.PPCo
 
 func CalcDraftsWKeel(
 	meanDraft types.MeanDraft, ppCorrections types.PPCorrections, v vessel.VesselData) types.DraftsWKeel {
-	keelCorrectionFwd := -1 * v.KeelFwd / 1000
-	keelCorrectionMid := -1 * v.KeelMid / 1000
-	keelCorrectionAft := -1 * v.KeelAft / 1000
+	keelFwd, keelMid, keelAft := v.KeelThickness()
+	keelCorrectionFwd := -1 * keelFwd.Metres()
+	keelCorrectionMid := -1 * keelMid.Metres()
+	keelCorrectionAft := -1 * keelAft.Metres()
 
 	return types.DraftsWKeel{
 		FwdDraftWKeel: round3(meanDraft.DraftFwdMean + ppCorrections.FwdCorrection + keelCorrectionFwd),
@@ -126,12 +137,13 @@ func CalcHydrostatics(mmc float64, hr []types.HydrostaticRow, v vessel.VesselDat
 	displacement := Interpolate(mmc, lower.Draft, lower.Displacement, upper.Draft, upper.Displacement)
 	tpc := Interpolate(mmc, lower.Draft, lower.TPC, upper.Draft, upper.TPC)
 	const k3 = 0.045
+	lbp := v.LBP.Metres()
 	lowerLcf := lower.LCF
 	upperLcf := upper.LCF
 
-	if lower.LCFDirection == types.LCFDirectionFromAP || lower.LCF > v.LBP*k3 {
-		lowerLcf = (v.LBP / 2) - lower.LCF
-		upperLcf = (v.LBP / 2) - upper.LCF
+	if lower.LCFDirection == types.LCFDirectionFromAP || lower.LCF > lbp*k3 {
+		lowerLcf = (lbp / 2) - lower.LCF
+		upperLcf = (lbp / 2) - upper.LCF
 	} else {
 		if lower.LCFDirection == types.LCFDirectionForward {
 			lowerLcf *= -1
@@ -180,10 +192,10 @@ func CalcSecondTrimCorrection(dwk types.DraftsWKeel, mtcRows []types.MTCRow, lbp
 }
 
 func CalcListCorrection(marks types.Marks, tpcListPort, tpcListStarboard float64) float64 {
-	if marks.MidPort == marks.MidStarboard {
+	if marks.MidPort.Metres() == marks.MidStarboard.Metres() {
 		return 0.0
 	}
-	return round3(6 * math.Abs(marks.MidPort.Value-marks.MidStarboard.Value) * math.Abs(tpcListPort-tpcListStarboard))
+	return round3(6 * math.Abs(marks.MidPort.Metres()-marks.MidStarboard.Metres()) * math.Abs(tpcListPort-tpcListStarboard))
 }
 
 func CalcDensityCorrection(displacement float64, firstTrim float64, secondTrim float64, listCorrection float64, density float64) float64 {
@@ -194,8 +206,9 @@ func CalcDensityCorrection(displacement float64, firstTrim float64, secondTrim f
 func CalcTotalDeductibles(bwt []types.BallastWaterTank, fwt []types.FreshWaterTank, d types.Deductibles) float64 {
 	tbw := TotalBallastWater(bwt)
 	tfw := TotalFreshWater(fwt)
+	tia := TotalIceAccretion(d.IceAccretion)
 
-	return round3(tbw + tfw + d.HFO + d.MDO + d.LubOil + d.BilgeWater + d.SewageWater + d.Others)
+	return round3(tbw + tfw + tia + (d.HFO + d.MDO + d.LubOil + d.BilgeWater + d.SewageWater + d.Others).Tonnes())
 }
 
 func CalcNetDisplacement(displacement, firstTrim, secondTrim, listCorrection, densityCorrection, totalDeductibles float64) float64 {
@@ -207,10 +220,10 @@ func CalcCargoWeight(netDisplacementIni, netDisplacementFin float64) float64 {
 	return round3(math.Abs(netDisplacementFin - netDisplacementIni))
 }
 
-func CalcConstant(netDisplacementIni float64, lightship float64) float64 {
-	return round3(netDisplacementIni - lightship)
+func CalcConstant(netDisplacementIni float64, lightship units.Mass) float64 {
+	return round3(netDisplacementIni - lightship.Tonnes())
 }
 
-func CalcCurrentDWT(displCorrToDensity float64, lightship float64) float64 {
-	return round3(displCorrToDensity - lightship)
+func CalcCurrentDWT(displCorrToDensity float64, lightship units.Mass) float64 {
+	return round3(displCorrToDensity - lightship.Tonnes())
 }
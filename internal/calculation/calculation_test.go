@@ -273,7 +273,7 @@ func TestCalcFirstTrimCorrection(t *testing.T) {
 	mmc := CalcMMC(draftsWKeel, vesselData)
 	hr := getInitHydrostaticRows()
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
-	firstTrimCorrectionGot := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
+	firstTrimCorrectionGot := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
 
 	if firstTrimCorrectionExpected != firstTrimCorrectionGot {
 		t.Errorf("Expected %f, got %f", firstTrimCorrectionExpected, firstTrimCorrectionGot)
@@ -288,7 +288,7 @@ func TestCalcSecondTrimCorrection(t *testing.T) {
 	ppCorrections := CalcFullLBPPPCorrections(meanDraft, vesselData)
 	draftsWKeel := CalcDraftsWKeel(meanDraft, ppCorrections, vesselData)
 	mtcRows := getInitMtcRows()
-	secondTrimCorrectionGot := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	secondTrimCorrectionGot := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 
 	if secondTrimCorrectionExpected != secondTrimCorrectionGot {
 		t.Errorf("Expected %f, got %f", secondTrimCorrectionExpected, secondTrimCorrectionGot)
@@ -317,10 +317,10 @@ func TestCalcDensityCorrection(t *testing.T) {
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
 	mtcRows := getInitMtcRows()
 	initDS := getInitDraftData()
-	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
-	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 	listCorrection := CalcListCorrection(marks, initDS.TPCListPort, initDS.TPCListStarboard)
-	densityCorrGot := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density)
+	densityCorrGot := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density.TonnesPerCubicMetre())
 
 	if densityCorrExpected != densityCorrGot {
 		t.Errorf("Expected %f, got %f", densityCorrExpected, densityCorrGot)
@@ -355,10 +355,10 @@ func TestCalcNetDisplacement(t *testing.T) {
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
 	mtcRows := getInitMtcRows()
 	initDS := getInitDraftData()
-	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
-	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 	listCorrection := CalcListCorrection(marks, initDS.TPCListPort, initDS.TPCListStarboard)
-	densityCorr := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density)
+	densityCorr := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density.TonnesPerCubicMetre())
 	netDisplacementGot := CalcNetDisplacement(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, densityCorr, totalDeductibles)
 	if netDisplacementExpected != netDisplacementGot {
 		t.Errorf("Expected %f, got %f", netDisplacementExpected, netDisplacementGot)
@@ -391,10 +391,10 @@ func TestCalcConstant(t *testing.T) {
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
 	mtcRows := getInitMtcRows()
 	initDS := getInitDraftData()
-	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
-	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 	listCorrection := CalcListCorrection(marks, initDS.TPCListPort, initDS.TPCListStarboard)
-	densityCorr := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density)
+	densityCorr := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density.TonnesPerCubicMetre())
 	netDisplacement := CalcNetDisplacement(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, densityCorr, totalDeductibles)
 	constantGot := CalcConstant(netDisplacement, vesselData.Lightship)
 	if constantExpected != constantGot {
@@ -414,13 +414,22 @@ func TestCalcCurrentDWT(t *testing.T) {
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
 	mtcRows := getInitMtcRows()
 	initDS := getInitDraftData()
-	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
-	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 	listCorrection := CalcListCorrection(marks, initDS.TPCListPort, initDS.TPCListStarboard)
-	densityCorr := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density)
+	densityCorr := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, initDS.Density.TonnesPerCubicMetre())
 	displCorrToDensity := round3(hydrostatics.Displacement + firstTrim + secondTrim + listCorrection + densityCorr)
 	DWTGot := CalcCurrentDWT(displCorrToDensity, vesselData.Lightship)
 	if constantExpected != DWTGot {
 		t.Errorf("Expected %f, got %f", constantExpected, DWTGot)
 	}
 }
+
+func hasWarning(warnings []types.Warning, code string) bool {
+	for _, w := range warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package calculation
+
+import (
+	"strings"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+type namedWeight struct {
+	name   string
+	weight float64
+}
+
+type weightsByName struct {
+	order  []string
+	names  map[string]string
+	weight map[string]float64
+}
+
+func groupWeights(weights []namedWeight) weightsByName {
+	g := weightsByName{names: make(map[string]string), weight: make(map[string]float64)}
+	for _, w := range weights {
+		k := strings.ToUpper(strings.TrimSpace(w.name))
+		if _, ok := g.names[k]; !ok {
+			g.order = append(g.order, k)
+			g.names[k] = w.name
+		}
+		g.weight[k] += w.weight
+	}
+	return g
+}
+
+func matchTanks(initial, final []namedWeight) ([]types.TankChange, float64) {
+	ini := groupWeights(initial)
+	fin := groupWeights(final)
+
+	var changes []types.TankChange
+	var total float64
+	for _, k := range ini.order {
+		_, inFinal := fin.names[k]
+		c := types.TankChange{
+			Name:        ini.names[k],
+			Initial:     round3(ini.weight[k]),
+			Final:       round3(fin.weight[k]),
+			OnlyInitial: !inFinal,
+		}
+		c.Change = round3(c.Final - c.Initial)
+		total += c.Change
+		changes = append(changes, c)
+	}
+	for _, k := range fin.order {
+		if _, inInitial := ini.names[k]; inInitial {
+			continue
+		}
+		c := types.TankChange{
+			Name:      fin.names[k],
+			Final:     round3(fin.weight[k]),
+			Change:    round3(fin.weight[k]),
+			OnlyFinal: true,
+		}
+		total += c.Change
+		changes = append(changes, c)
+	}
+	return changes, round3(total)
+}
+
+func ballastWeights(tanks []types.BallastWaterTank) []namedWeight {
+	weights := make([]namedWeight, len(tanks))
+	for i, t := range tanks {
+		weights[i] = namedWeight{name: t.Name, weight: round3(t.GetWeight())}
+	}
+	return weights
+}
+
+func freshWaterWeights(tanks []types.FreshWaterTank) []namedWeight {
+	weights := make([]namedWeight, len(tanks))
+	for i, t := range tanks {
+		weights[i] = namedWeight{name: t.Name, weight: round3(t.GetWeight())}
+	}
+	return weights
+}
+
+func deductibleWeights(d types.Deductibles) []namedWeight {
+	weights := []namedWeight{
+		{name: "HFO", weight: d.HFO.Tonnes()},
+		{name: "MDO", weight: d.MDO.Tonnes()},
+		{name: "Lub oil", weight: d.LubOil.Tonnes()},
+		{name: "Bilge water", weight: d.BilgeWater.Tonnes()},
+		{name: "Sewage water", weight: d.SewageWater.Tonnes()},
+	}
+	if d.Others != 0 || d.OthersName != "" {
+		name := d.OthersName
+		if name == "" {
+			name = "Others"
+		}
+		weights = append(weights, namedWeight{name: name, weight: d.Others.Tonnes()})
+	}
+	for _, ia := range d.IceAccretion {
+		weights = append(weights, namedWeight{name: "Ice " + ia.Zone, weight: round3(ia.GetWeight())})
+	}
+	return weights
+}
+
+func CalcDeductiblesChange(initial types.InitialDraft, final types.FinalDraft) types.DeductiblesChange {
+	var change types.DeductiblesChange
+	change.Ballast, change.BallastTotal = matchTanks(
+		ballastWeights(initial.BallastWaterTanks), ballastWeights(final.BallastWaterTanks))
+	change.FreshWater, change.FreshWaterTotal = matchTanks(
+		freshWaterWeights(initial.FreshWaterTanks), freshWaterWeights(final.FreshWaterTanks))
+	change.Deductibles, change.DeductiblesTotal = matchTanks(
+		deductibleWeights(initial.Deductibles), deductibleWeights(final.Deductibles))
+	change.Total = round3(change.BallastTotal + change.FreshWaterTotal + change.DeductiblesTotal)
+	return change
+}
@@ -0,0 +1,55 @@
+package calculation
+
+import (
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func TestCalcDeductiblesChange(t *testing.T) {
+	initial := types.InitialDraft{
+		BallastWaterTanks: []types.BallastWaterTank{
+			{Name: "FPT", Volume: 1000, Density: 1.025},
+			{Name: "WBT 1P", Volume: 2000, Density: 1.020},
+			{Name: "APT", Volume: 500, Density: 1.025},
+		},
+		FreshWaterTanks: []types.FreshWaterTank{{Name: "FW P", Volume: 150}},
+		Deductibles:     types.Deductibles{HFO: 700, MDO: 90},
+	}
+	final := types.FinalDraft{
+		BallastWaterTanks: []types.BallastWaterTank{
+			{Name: "fpt ", Volume: 100, Density: 1.025},
+			{Name: "WBT 1P", Volume: 0, Density: 1.020},
+			{Name: "WBT 2S", Volume: 50, Density: 1.000},
+		},
+		FreshWaterTanks: []types.FreshWaterTank{{Name: "FW P", Volume: 120}},
+		Deductibles:     types.Deductibles{HFO: 680.5, MDO: 88},
+	}
+
+	got := CalcDeductiblesChange(initial, final)
+
+	if len(got.Ballast) != 4 {
+		t.Fatalf("Expected 4 ballast tanks, got %d", len(got.Ballast))
+	}
+	if got.Ballast[0].Name != "FPT" || got.Ballast[0].Change != -922.5 {
+		t.Errorf("FPT: expected -922.500, got %s %f", got.Ballast[0].Name, got.Ballast[0].Change)
+	}
+	if !got.Ballast[2].OnlyInitial || got.Ballast[2].Change != -512.5 {
+		t.Errorf("APT: expected initial only, -512.500, got %+v", got.Ballast[2])
+	}
+	if !got.Ballast[3].OnlyFinal || got.Ballast[3].Change != 50 {
+		t.Errorf("WBT 2S: expected final only, 50.000, got %+v", got.Ballast[3])
+	}
+	if got.BallastTotal != -3425 {
+		t.Errorf("Ballast total: expected -3425.000, got %f", got.BallastTotal)
+	}
+	if got.FreshWaterTotal != -30 {
+		t.Errorf("Fresh water total: expected -30.000, got %f", got.FreshWaterTotal)
+	}
+	if got.DeductiblesTotal != -21.5 {
+		t.Errorf("Deductibles total: expected -21.500, got %f", got.DeductiblesTotal)
+	}
+	if got.Total != -3476.5 {
+		t.Errorf("Total: expected -3476.500, got %f", got.Total)
+	}
+}
@@ -0,0 +1,75 @@
+package calculation
+
+import (
+	"errors"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+var (
+	ErrHydrostaticRows = errors.New("calculation: two hydrostatic rows are required")
+	ErrMTCRows         = errors.New("calculation: two MTC rows are required")
+	ErrVesselType      = errors.New("calculation: unknown vessel type")
+	ErrLBP             = errors.New("calculation: vessel LBP is not set")
+)
+
+func CalcPPCorrections(m types.MeanDraft, v vessel.VesselData) types.PPCorrections {
+	if v.CorrectionMethod == vessel.CorrectionMethodHalfLBP {
+		return CalcHalfLBPPPCorrections(m, v)
+	}
+	return CalcFullLBPPPCorrections(m, v)
+}
+
+func CalcCondition(c types.Condition, v vessel.VesselData) (types.ConditionResult, error) {
+	c = c.Metric()
+	if len(c.HydrostaticRows) < 2 {
+		return types.ConditionResult{}, ErrHydrostaticRows
+	}
+	if len(c.MTCRows) < 2 {
+		return types.ConditionResult{}, ErrMTCRows
+	}
+	if v.LBP <= 0 {
+		return types.ConditionResult{}, ErrLBP
+	}
+	switch v.VesselType {
+	case vessel.VesselTypeMarine, vessel.VesselTypeRiver, vessel.VesselTypeBarge:
+	default:
+		return types.ConditionResult{}, ErrVesselType
+	}
+
+	if len(v.MarkLayout) > 0 {
+		marks, resolved, err := ResolveMarks(c.Marks, v)
+		if err != nil {
+			return types.ConditionResult{}, err
+		}
+		c.Marks, v = marks, resolved
+	}
+
+	meanDraft := MeanDrafts(c.Marks)
+	ppCorrections := CalcPPCorrections(meanDraft, v)
+	draftsWKeel := CalcDraftsWKeel(meanDraft, ppCorrections, v)
+	mmc := CalcMMC(draftsWKeel, v)
+	hydrostatics := CalcHydrostatics(mmc, c.HydrostaticRows, v)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, v.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, c.MTCRows, v.LBP.Metres())
+	listCorrection := CalcListCorrection(c.Marks, c.TPCListPort, c.TPCListStarboard)
+	densityCorrection := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, c.Density.TonnesPerCubicMetre())
+	totalDeductibles := CalcTotalDeductibles(c.BallastWaterTanks, c.FreshWaterTanks, c.Deductibles)
+
+	return types.ConditionResult{
+		MeanDraft:            meanDraft,
+		PPCorrections:        ppCorrections,
+		DraftsWKeel:          draftsWKeel,
+		MMC:                  mmc,
+		Hydrostatics:         hydrostatics,
+		FirstTrimCorrection:  firstTrim,
+		SecondTrimCorrection: secondTrim,
+		ListCorrection:       listCorrection,
+		DensityCorrection:    densityCorrection,
+		DisplCorrToDensity:   round3(hydrostatics.Displacement + firstTrim + secondTrim + listCorrection + densityCorrection),
+		TotalDeductibles:     totalDeductibles,
+		NetDisplacement: CalcNetDisplacement(
+			hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, densityCorrection, totalDeductibles),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package calculation
+
+import (
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func CalcConstantSurvey(
+	s *types.Survey, history []types.ConstantRecord, threshold types.ConstantThreshold,
+) (types.ConstantResult, error) {
+	condition, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData)
+	if err != nil {
+		return types.ConstantResult{}, err
+	}
+
+	constant := CalcConstant(condition.NetDisplacement, s.VesselData.Lightship)
+	stats := ConstantStatistics(history)
+	result := types.ConstantResult{
+		Condition:    condition,
+		Constant:     constant,
+		Declared:     s.InitialDraft.ConstantDeclared.Tonnes(),
+		DeclaredDiff: round3(constant - s.InitialDraft.ConstantDeclared.Tonnes()),
+		History:      stats,
+	}
+
+	if stats.Count > 0 {
+		result.HistoryDiff = round3(constant - stats.Mean)
+	}
+	result.Warnings = append(result.Warnings, CheckSeaCondition("initial", s.InitialDraft.SeaCondition)...)
+	if w := CheckConstantAnomaly(constant, stats, threshold); w != nil {
+		result.Warnings = append(result.Warnings, *w)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package calculation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+func getConstantSurvey() *types.Survey {
+	initDS := getInitDraftData()
+	initDS.Marks = getMarks()
+	initDS.HydrostaticRows = getInitHydrostaticRows()
+	initDS.MTCRows = getInitMtcRows()
+	initDS.BallastWaterTanks = getInitBallastWaterTanks()
+	initDS.FreshWaterTanks = getInitFreshWaterTanks()
+	initDS.Deductibles = getInitDeductibles()
+	initDS.ConstantDeclared = 600.000
+
+	return &types.Survey{
+		Kind:         types.SurveyKindConstant,
+		InitialDraft: initDS,
+		VesselData:   getVesselData(),
+	}
+}
+
+func TestCalcCondition(t *testing.T) {
+	netDisplacementExpected := 9021.111
+	s := getConstantSurvey()
+	got, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if netDisplacementExpected != got.NetDisplacement {
+		t.Errorf("Expected %f, got %f", netDisplacementExpected, got.NetDisplacement)
+	}
+}
+
+func TestCalcCondition_MissingRows(t *testing.T) {
+	s := getConstantSurvey()
+	s.InitialDraft.HydrostaticRows = s.InitialDraft.HydrostaticRows[:1]
+	if _, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData); err != ErrHydrostaticRows {
+		t.Errorf("Expected %v, got %v", ErrHydrostaticRows, err)
+	}
+}
+
+func TestCalcSurvey(t *testing.T) {
+	tolerances := types.Tolerances{Default: types.Tolerance{WarningPercent: 0.5, LimitPercent: 1}}
+	tests := []struct {
+		name        string
+		survey      *types.Survey
+		summerDraft float64
+		summerDWT   float64
+		overloaded  bool
+		ice         string
+		ballast     float64
+	}{
+		{name: "loading", survey: getLoadingSurvey(), summerDraft: 4.500, summerDWT: 12000.000, overloaded: true, ice: "final", ballast: -10606.596},
+		{name: "discharge", survey: getDischargeSurvey(), summerDraft: 12.200, summerDWT: 56000.000, ice: "initial", ballast: 10606.596},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.survey
+			s.VesselData.SummerDraft, s.VesselData.SummerDWT, s.VesselData.SummerTPC = units.Metres(tt.summerDraft), units.Tonnes(tt.summerDWT), 49.700
+			s.CargoOperation.Figures.BillOfLading = 10500
+			s.FinalDraft.CargoDeclared = 10650
+			s.CargoOperation.Parcels = []types.Parcel{{BLNumber: "1", Declared: 7000}, {BLNumber: "2", Declared: 3500}}
+			ice := types.SeaCondition{Type: types.SeaConditionTypeIce, Ice: types.IceCondition020To030}
+			if tt.ice == "initial" {
+				s.InitialDraft.SeaCondition = ice
+			} else {
+				s.FinalDraft.SeaCondition = ice
+			}
+
+			got, err := CalcSurvey(s, tolerances, nil, DefaultConstantThreshold())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Cargo != 10606.596 {
+				t.Errorf("Cargo: expected 10606.596, got %f", got.Cargo)
+			}
+			if got.Constant != 631.111 || got.ConstantDeclaredDiff != 31.111 {
+				t.Errorf("Constant: expected 631.111 (diff 31.111), got %f (%f)", got.Constant, got.ConstantDeclaredDiff)
+			}
+			if got.DeductiblesChange.BallastTotal != tt.ballast || got.DeductiblesChange.Total != tt.ballast {
+				t.Errorf("Ballast change: expected %f, got %f (total %f)",
+					tt.ballast, got.DeductiblesChange.BallastTotal, got.DeductiblesChange.Total)
+			}
+
+			expectedFigures := []types.FigureDifference{
+				{Source: types.FigureSourceBillOfLading, Declared: 10500, Difference: 106.596, Percent: 1.015, Status: types.ReconciliationExceeded},
+				{Source: types.FigureSourceShipFigure, Declared: 10650, Difference: -43.404, Percent: -0.408, Status: types.ReconciliationWithin},
+			}
+			if !reflect.DeepEqual(expectedFigures, got.Reconciliation) {
+				t.Errorf("Reconciliation: expected %v, got %v", expectedFigures, got.Reconciliation)
+			}
+			if len(got.Parcels) != 2 || got.Parcels[0].Quantity != 7071.064 || got.Parcels[1].Quantity != 3535.532 {
+				t.Errorf("Parcels: expected 7071.064/3535.532, got %v", got.Parcels)
+			}
+
+			if got.LoadLine == nil {
+				t.Fatalf("Expected load line result, got %v", got.Warnings)
+			}
+			if got.LoadLine.Overloaded != tt.overloaded || got.LoadLine.MMC != 4.542 {
+				t.Errorf("Load line: expected overloaded %v at 4.542, got %v at %f",
+					tt.overloaded, got.LoadLine.Overloaded, got.LoadLine.MMC)
+			}
+
+			if len(got.Warnings) != 1 || got.Warnings[0].Code != WarningIceReadings ||
+				!strings.HasPrefix(got.Warnings[0].Message, tt.ice) {
+				t.Errorf("Expected %s %s warning only, got %v", tt.ice, WarningIceReadings, got.Warnings)
+			}
+		})
+	}
+}
+
+func TestCalcConstantSurvey(t *testing.T) {
+	s := getConstantSurvey()
+	history := []types.ConstantRecord{{Constant: 620.000}, {Constant: 640.000}}
+	got, err := CalcConstantSurvey(s, history, DefaultConstantThreshold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Constant != 631.111 {
+		t.Errorf("Constant: expected 631.111, got %f", got.Constant)
+	}
+	if got.DeclaredDiff != 31.111 {
+		t.Errorf("Declared diff: expected 31.111, got %f", got.DeclaredDiff)
+	}
+	if got.History.Mean != 630.000 {
+		t.Errorf("History mean: expected 630.000, got %f", got.History.Mean)
+	}
+	if got.HistoryDiff != 1.111 {
+		t.Errorf("History diff: expected 1.111, got %f", got.HistoryDiff)
+	}
+}
@@ -0,0 +1,52 @@
+package calculation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+var ErrEmptyConvoy = errors.New("calculation: convoy has no units")
+
+func CalcConvoySurvey(s *types.Survey, tolerances types.Tolerances) (types.ConvoyResult, error) {
+	if len(s.Convoy) == 0 {
+		return types.ConvoyResult{}, ErrEmptyConvoy
+	}
+
+	var result types.ConvoyResult
+	var total float64
+	for i, unit := range s.Convoy {
+		v := unit.VesselData
+		if v.VesselType == "" {
+			v.VesselType = vessel.VesselTypeBarge
+		}
+		name := v.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		initial, err := CalcCondition(unit.InitialDraft.Condition(), v)
+		if err != nil {
+			return types.ConvoyResult{}, fmt.Errorf("convoy unit %s initial: %w", name, err)
+		}
+		final, err := CalcCondition(unit.FinalDraft.Condition(), v)
+		if err != nil {
+			return types.ConvoyResult{}, fmt.Errorf("convoy unit %s final: %w", name, err)
+		}
+
+		cargo := CalcCargoWeight(initial.NetDisplacement, final.NetDisplacement)
+		total += cargo
+		result.Units = append(result.Units, types.ConvoyUnitResult{
+			Name:    name,
+			Initial: initial,
+			Final:   final,
+			Cargo:   cargo,
+		})
+	}
+
+	result.TotalCargo = round3(total)
+	result.Reconciliation = Reconcile(result.TotalCargo, s, tolerances)
+	return result, nil
+}
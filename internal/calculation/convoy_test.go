@@ -0,0 +1,72 @@
+package calculation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getEvenKeelMarks(draft float64) types.Marks {
+	m := types.Mark{Value: draft}
+	return types.Marks{FwdPort: m, FwdStarboard: m, MidPort: m, MidStarboard: m, AftPort: m, AftStarboard: m}
+}
+
+func getBargeCondition(draft float64) (types.Marks, []types.HydrostaticRow, []types.MTCRow) {
+	return getEvenKeelMarks(draft),
+		[]types.HydrostaticRow{
+			{Draft: 1.0, Displacement: 1000, TPC: 10, LCF: 0, LCFDirection: types.LCFDirectionAft},
+			{Draft: 3.0, Displacement: 3000, TPC: 10, LCF: 0, LCFDirection: types.LCFDirectionAft},
+		},
+		[]types.MTCRow{{Draft: 1.0, MTC: 100}, {Draft: 2.0, MTC: 100}}
+}
+
+func getConvoyUnit(name string, initialDraft, finalDraft float64) types.ConvoyUnit {
+	unit := types.ConvoyUnit{VesselData: vessel.VesselData{Name: name, LBP: 80}}
+	unit.InitialDraft.Marks, unit.InitialDraft.HydrostaticRows, unit.InitialDraft.MTCRows = getBargeCondition(initialDraft)
+	unit.InitialDraft.Density = 1.025
+	unit.FinalDraft.Marks, unit.FinalDraft.HydrostaticRows, unit.FinalDraft.MTCRows = getBargeCondition(finalDraft)
+	unit.FinalDraft.Density = 1.025
+	return unit
+}
+
+func TestCalcConvoySurvey(t *testing.T) {
+	s := &types.Survey{
+		Kind: types.SurveyKindConvoy,
+		Convoy: []types.ConvoyUnit{
+			getConvoyUnit("B-1", 1.5, 2.5),
+			getConvoyUnit("B-2", 1.5, 2.0),
+		},
+		CargoOperation: types.CargoOperation{Figures: types.CargoFigures{BillOfLading: 1500}},
+	}
+	got, err := CalcConvoySurvey(s, getTolerances())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Units) != 2 {
+		t.Fatalf("Expected 2 units, got %d", len(got.Units))
+	}
+	if got.Units[0].Cargo != 1000 || got.Units[1].Cargo != 500 {
+		t.Errorf("Expected 1000 and 500, got %f and %f", got.Units[0].Cargo, got.Units[1].Cargo)
+	}
+	if got.TotalCargo != 1500 {
+		t.Errorf("Total: expected 1500, got %f", got.TotalCargo)
+	}
+	if len(got.Reconciliation) != 1 || got.Reconciliation[0].Difference != 0 {
+		t.Errorf("Expected zero B/L difference, got %+v", got.Reconciliation)
+	}
+}
+
+func TestCalcConvoySurvey_Errors(t *testing.T) {
+	if _, err := CalcConvoySurvey(&types.Survey{}, types.Tolerances{}); err != ErrEmptyConvoy {
+		t.Errorf("Expected %v, got %v", ErrEmptyConvoy, err)
+	}
+
+	unit := getConvoyUnit("B-1", 1.5, 2.5)
+	unit.FinalDraft.HydrostaticRows = nil
+	_, err := CalcConvoySurvey(&types.Survey{Convoy: []types.ConvoyUnit{unit}}, types.Tolerances{})
+	if !errors.Is(err, ErrHydrostaticRows) {
+		t.Errorf("Expected %v, got %v", ErrHydrostaticRows, err)
+	}
+}
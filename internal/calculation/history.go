@@ -0,0 +1,126 @@
+package calculation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+const WarningConstantAnomaly = "constant_anomaly"
+
+func DefaultConstantThreshold() types.ConstantThreshold {
+	return types.ConstantThreshold{MinSurveys: 3, Sigma: 2, Tonnes: 50}
+}
+
+// ConstantHistory calculates the constant of every stored survey of the vessel
+// with the given IMO from its light condition. Surveys whose light condition
+// cannot be calculated are skipped. Records are ordered by the start time of
+// that condition.
+func ConstantHistory(surveys []*types.Survey, imo, excludeID string) []types.ConstantRecord {
+	var records []types.ConstantRecord
+	for _, s := range surveys {
+		if s == nil || s.VesselData.IMO != imo || s.ID == excludeID {
+			continue
+		}
+		if s.Kind != "" && s.Kind != types.SurveyKindDraft && s.Kind != types.SurveyKindConstant {
+			continue
+		}
+		condition, date, err := surveyLightCondition(s)
+		if err != nil {
+			continue
+		}
+		records = append(records, types.ConstantRecord{
+			SurveyID: s.ID,
+			Date:     date,
+			Constant: CalcConstant(condition.NetDisplacement, s.VesselData.Lightship),
+		})
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Date.Before(records[j].Date) })
+	return records
+}
+
+// surveyLightCondition calculates the conditions of a survey and returns its
+// light condition, see lightCondition.
+func surveyLightCondition(s *types.Survey) (types.ConditionResult, time.Time, error) {
+	initial, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData)
+	if err != nil || s.Kind == types.SurveyKindConstant {
+		return initial, s.InitialDraft.StartedAt, err
+	}
+	final, err := CalcCondition(s.FinalDraft.Condition(), s.VesselData)
+	if err != nil {
+		return types.ConditionResult{}, time.Time{}, err
+	}
+	condition, date := lightCondition(s, initial, final)
+	return condition, date, nil
+}
+
+// lightCondition returns the condition the constant of a survey is taken from:
+// the initial one for constant surveys and, for draft surveys, the one with the
+// lower net displacement, so the cargo of a discharge survey is not counted.
+func lightCondition(s *types.Survey, initial, final types.ConditionResult) (types.ConditionResult, time.Time) {
+	if s.Kind != types.SurveyKindConstant && final.NetDisplacement < initial.NetDisplacement {
+		return final, s.FinalDraft.StartedAt
+	}
+	return initial, s.InitialDraft.StartedAt
+}
+
+func ConstantStatistics(records []types.ConstantRecord) types.ConstantStats {
+	stats := types.ConstantStats{Count: len(records)}
+	if len(records) == 0 {
+		return stats
+	}
+
+	stats.Min, stats.Max = records[0].Constant, records[0].Constant
+	var sum float64
+	for _, r := range records {
+		sum += r.Constant
+		stats.Min = math.Min(stats.Min, r.Constant)
+		stats.Max = math.Max(stats.Max, r.Constant)
+	}
+	m := sum / float64(len(records))
+
+	var squares, sxy, sxx float64
+	start := records[0].Date
+	var meanYears float64
+	for _, r := range records {
+		meanYears += r.Date.Sub(start).Hours() / (24 * 365.25)
+	}
+	meanYears /= float64(len(records))
+	for _, r := range records {
+		squares += (r.Constant - m) * (r.Constant - m)
+		x := r.Date.Sub(start).Hours()/(24*365.25) - meanYears
+		sxy += x * (r.Constant - m)
+		sxx += x * x
+	}
+
+	stats.Mean = round3(m)
+	if len(records) > 1 {
+		stats.StdDev = round3(math.Sqrt(squares / float64(len(records)-1)))
+	}
+	if sxx > 0 {
+		stats.TrendPerYr = round3(sxy / sxx)
+	}
+	return stats
+}
+
+func CheckConstantAnomaly(
+	constant float64, stats types.ConstantStats, threshold types.ConstantThreshold) *types.Warning {
+	if stats.Count == 0 || stats.Count < threshold.MinSurveys {
+		return nil
+	}
+	band := threshold.Sigma*stats.StdDev + threshold.Tonnes
+	deviation := round3(constant - stats.Mean)
+	if math.Abs(deviation) <= band {
+		return nil
+	}
+	return &types.Warning{
+		Code:     WarningConstantAnomaly,
+		Severity: types.SeverityWarning,
+		Message: fmt.Sprintf(
+			"constant %.3f MT deviates from vessel history mean %.3f MT by %.3f MT (band ±%.3f MT): check for unreported ballast or bunkers",
+			constant, stats.Mean, deviation, band),
+	}
+}
@@ -0,0 +1,181 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func getConstantRecords() []types.ConstantRecord {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []types.ConstantRecord
+	for i, c := range []float64{600, 610, 620, 630} {
+		records = append(records, types.ConstantRecord{
+			SurveyID: string(rune('a' + i)),
+			Date:     start.AddDate(0, 0, i*365),
+			Constant: c,
+		})
+	}
+	return records
+}
+
+func TestConstantHistory(t *testing.T) {
+	own := getConstantSurvey()
+	own.ID = "own"
+	own.VesselData.IMO = "9000001"
+	other := getConstantSurvey()
+	other.ID = "other"
+	other.VesselData.IMO = "9000002"
+	current := getConstantSurvey()
+	current.ID = "current"
+	current.VesselData.IMO = "9000001"
+	broken := getConstantSurvey()
+	broken.ID = "broken"
+	broken.VesselData.IMO = "9000001"
+	broken.InitialDraft.HydrostaticRows = nil
+
+	got := ConstantHistory([]*types.Survey{own, other, current, broken}, "9000001", "current")
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(got))
+	}
+	if got[0].SurveyID != "own" || got[0].Constant != 631.111 {
+		t.Errorf("Expected own 631.111, got %s %f", got[0].SurveyID, got[0].Constant)
+	}
+}
+
+// getDischargeSurvey returns a draft survey whose final condition is the
+// light one of getConstantSurvey and whose initial condition carries cargo.
+func getDischargeSurvey() *types.Survey {
+	light := getConstantSurvey().InitialDraft
+	loaded := light
+	loaded.BallastWaterTanks = nil
+	loaded.StartedAt = time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	discharge := getConstantSurvey()
+	discharge.ID = "discharge"
+	discharge.Kind = types.SurveyKindDraft
+	discharge.VesselData.IMO = "9000001"
+	discharge.InitialDraft = loaded
+	discharge.FinalDraft = types.FinalDraft{
+		BallastWaterTanks: light.BallastWaterTanks,
+		FreshWaterTanks:   light.FreshWaterTanks,
+		Deductibles:       light.Deductibles,
+		Marks:             light.Marks,
+		Density:           light.Density,
+		StartedAt:         loaded.StartedAt.Add(48 * time.Hour),
+		MTCRows:           light.MTCRows,
+		HydrostaticRows:   light.HydrostaticRows,
+		TPCListPort:       light.TPCListPort,
+		TPCListStarboard:  light.TPCListStarboard,
+	}
+	return discharge
+}
+
+func TestConstantHistory_DischargeSurvey(t *testing.T) {
+	discharge := getDischargeSurvey()
+	got := ConstantHistory([]*types.Survey{discharge}, "9000001", "")
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(got))
+	}
+	if got[0].Constant != 631.111 {
+		t.Errorf("Expected constant of the final condition 631.111, got %f", got[0].Constant)
+	}
+	if !got[0].Date.Equal(discharge.FinalDraft.StartedAt) {
+		t.Errorf("Expected %v, got %v", discharge.FinalDraft.StartedAt, got[0].Date)
+	}
+}
+
+func TestCalcSurvey_DischargeConstant(t *testing.T) {
+	discharge := getDischargeSurvey()
+	discharge.InitialDraft.ConstantDeclared = 600
+
+	got, err := CalcSurvey(discharge, types.Tolerances{}, getConstantRecords(), DefaultConstantThreshold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Constant != 631.111 || got.ConstantDeclaredDiff != 31.111 {
+		t.Errorf("Expected constant 631.111 (diff 31.111), got %f (%f)", got.Constant, got.ConstantDeclaredDiff)
+	}
+	if hasWarning(got.Warnings, WarningConstantAnomaly) {
+		t.Errorf("Expected no %s warning, got %v", WarningConstantAnomaly, got.Warnings)
+	}
+
+	var history []types.ConstantRecord
+	for _, r := range getConstantRecords() {
+		r.Constant += 100
+		history = append(history, r)
+	}
+	got, err = CalcSurvey(discharge, types.Tolerances{}, history, DefaultConstantThreshold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasWarning(got.Warnings, WarningConstantAnomaly) {
+		t.Errorf("Expected %s warning, got %v", WarningConstantAnomaly, got.Warnings)
+	}
+}
+
+func TestConstantStatistics(t *testing.T) {
+	got := ConstantStatistics(getConstantRecords())
+	if got.Count != 4 || got.Mean != 615 || got.Min != 600 || got.Max != 630 {
+		t.Errorf("Expected 4/615/600/630, got %d/%f/%f/%f", got.Count, got.Mean, got.Min, got.Max)
+	}
+	if got.StdDev != 12.91 {
+		t.Errorf("StdDev: expected 12.910, got %f", got.StdDev)
+	}
+	if got.TrendPerYr != 10.007 {
+		t.Errorf("Trend: expected 10.007, got %f", got.TrendPerYr)
+	}
+}
+
+func TestCheckConstantAnomaly(t *testing.T) {
+	stats := ConstantStatistics(getConstantRecords())
+	threshold := DefaultConstantThreshold()
+
+	if w := CheckConstantAnomaly(650, stats, threshold); w != nil {
+		t.Errorf("Expected no warning, got %s", w.Message)
+	}
+	w := CheckConstantAnomaly(720, stats, threshold)
+	if w == nil || w.Code != WarningConstantAnomaly {
+		t.Fatalf("Expected %s warning, got %v", WarningConstantAnomaly, w)
+	}
+
+	threshold.MinSurveys = 5
+	if w := CheckConstantAnomaly(720, stats, threshold); w != nil {
+		t.Errorf("Expected no warning with short history, got %s", w.Message)
+	}
+}
+
+// getLoadingSurvey returns getDischargeSurvey with the conditions swapped: the
+// vessel arrives light and leaves with cargo.
+func getLoadingSurvey() *types.Survey {
+	s := getDischargeSurvey()
+	s.ID = "loading"
+	initial, final := s.InitialDraft, s.FinalDraft
+	s.InitialDraft = types.InitialDraft{
+		BallastWaterTanks: final.BallastWaterTanks,
+		FreshWaterTanks:   final.FreshWaterTanks,
+		Deductibles:       final.Deductibles,
+		Marks:             final.Marks,
+		ConstantDeclared:  initial.ConstantDeclared,
+		Density:           final.Density,
+		StartedAt:         initial.StartedAt,
+		MTCRows:           final.MTCRows,
+		HydrostaticRows:   final.HydrostaticRows,
+		TPCListPort:       final.TPCListPort,
+		TPCListStarboard:  final.TPCListStarboard,
+	}
+	s.FinalDraft = types.FinalDraft{
+		BallastWaterTanks: initial.BallastWaterTanks,
+		FreshWaterTanks:   initial.FreshWaterTanks,
+		Deductibles:       initial.Deductibles,
+		Marks:             initial.Marks,
+		Density:           initial.Density,
+		StartedAt:         final.StartedAt,
+		MTCRows:           initial.MTCRows,
+		HydrostaticRows:   initial.HydrostaticRows,
+		TPCListPort:       initial.TPCListPort,
+		TPCListStarboard:  initial.TPCListStarboard,
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package calculation
+
+import (
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func getIceAccretion() []types.IceAccretion {
+	return []types.IceAccretion{
+		{Zone: "Main deck", Area: 4500, Thickness: 0.05, Density: 0.9},
+		{Zone: "Hatch covers", Area: 2800, Thickness: 0.10, Density: 0.3},
+		{Zone: "Hull above WL", Area: 600, Thickness: 0.08},
+	}
+}
+
+func TestTotalIceAccretion(t *testing.T) {
+	got := TotalIceAccretion(getIceAccretion())
+	if got != 329.7 {
+		t.Errorf("Expected 329.700, got %f", got)
+	}
+}
+
+func TestCalcTotalDeductibles_IceAccretion(t *testing.T) {
+	totalDeductiblesExpected := 12073.294
+	d := getInitDeductibles()
+	d.IceAccretion = getIceAccretion()
+	got := CalcTotalDeductibles(getInitBallastWaterTanks(), getInitFreshWaterTanks(), d)
+
+	if totalDeductiblesExpected != got {
+		t.Errorf("Expected %f, got %f", totalDeductiblesExpected, got)
+	}
+}
+
+func TestCheckSeaCondition(t *testing.T) {
+	if got := CheckSeaCondition("initial", types.SeaCondition{Type: types.SeaConditionTypeWave, Wave: types.WaveConditionRough}); got != nil {
+		t.Errorf("Expected no warnings for waves, got %v", got)
+	}
+	if got := CheckSeaCondition("initial", types.SeaCondition{Type: types.SeaConditionTypeIce, Ice: types.IceCondition005To010}); got != nil {
+		t.Errorf("Expected no warnings for light ice, got %v", got)
+	}
+	got := CheckSeaCondition("final", types.SeaCondition{Type: types.SeaConditionTypeIce, Ice: types.IceCondition040To060})
+	if len(got) != 1 || got[0].Code != WarningIceReadings || got[0].Severity != types.SeverityError {
+		t.Errorf("Expected one %s error, got %v", WarningIceReadings, got)
+	}
+}
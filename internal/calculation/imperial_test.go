@@ -0,0 +1,99 @@
+package calculation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+// imperialTable converts a metric hydrostatic table into the imperial one
+// read from the vessel's booklet.
+func imperialTable(rows []types.HydrostaticRow) []types.HydrostaticRow {
+	imperial := make([]types.HydrostaticRow, len(rows))
+	for i, r := range rows {
+		imperial[i] = types.HydrostaticRow{
+			Draft:        r.Draft / 0.3048,
+			Displacement: r.Displacement / 1.0160469088,
+			TPC:          types.TPCToTPI(r.TPC),
+			LCF:          r.LCF / 0.3048,
+			LCFDirection: r.LCFDirection,
+		}
+	}
+	return imperial
+}
+
+func TestMeanDrafts_Imperial(t *testing.T) {
+	marks := getMarks()
+	marks.FwdPort = types.Mark{Unit: types.DraftUnitFeetInches, Feet: 10, Inches: 6}
+	marks.FwdStarboard = types.Mark{Unit: types.DraftUnitFeetInches, Feet: 10, Inches: 7}
+	got := MeanDrafts(marks)
+
+	if got.DraftFwdMean != 3.213 {
+		t.Errorf("meanF: expected 3.213, got %f", got.DraftFwdMean)
+	}
+	if got.DraftMidMean != 4.525 {
+		t.Errorf("meanM: expected 4.525, got %f", got.DraftMidMean)
+	}
+}
+
+func TestMark_String(t *testing.T) {
+	imperial := types.Mark{Unit: types.DraftUnitFeetInches, Feet: 10, Inches: 6.5}
+	if got := imperial.String(); got != `10' 6.5"` {
+		t.Errorf(`Expected 10' 6.5", got %s`, got)
+	}
+	metric := types.Mark{Value: 4.51}
+	if got := metric.String(); got != "4.510 m" {
+		t.Errorf("Expected 4.510 m, got %s", got)
+	}
+}
+
+func TestTPCToTPI(t *testing.T) {
+	const tpc = 57.2
+	if got := types.TPIToTPC(types.TPCToTPI(tpc)); math.Abs(got-tpc) > 1e-9 {
+		t.Errorf("Expected %f, got %f", tpc, got)
+	}
+	if got := types.TPCToTPI(1); math.Abs(got-2.54/1.0160469088) > 1e-9 {
+		t.Errorf("Expected %f, got %f", 2.54/1.0160469088, got)
+	}
+}
+
+func TestCalcCondition_ImperialTable(t *testing.T) {
+	const ftPerM = 1 / 0.3048
+	const ltPerT = 1 / 1.0160469088
+	const tpiPerTPC = 2.54 / 1.0160469088
+	const mtiPerMTC = 2.54 / (1.0160469088 * 0.3048)
+
+	s := getConstantSurvey()
+	expected, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := s.InitialDraft.Condition()
+	c.HydrostaticUnit = types.HydrostaticUnitImperial
+	c.HydrostaticRows = nil
+	for _, r := range getInitHydrostaticRows() {
+		c.HydrostaticRows = append(c.HydrostaticRows, types.HydrostaticRow{
+			Draft:        r.Draft * ftPerM,
+			Displacement: r.Displacement * ltPerT,
+			TPC:          r.TPC * tpiPerTPC,
+			LCF:          r.LCF * ftPerM,
+			LCFDirection: r.LCFDirection,
+		})
+	}
+	c.MTCRows = nil
+	for _, r := range getInitMtcRows() {
+		c.MTCRows = append(c.MTCRows, types.MTCRow{Draft: r.Draft * ftPerM, MTC: r.MTC * mtiPerMTC})
+	}
+	c.TPCListPort *= tpiPerTPC
+	c.TPCListStarboard *= tpiPerTPC
+
+	got, err := CalcCondition(c, s.VesselData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(expected.NetDisplacement-got.NetDisplacement) > 0.01 {
+		t.Errorf("Expected %f, got %f", expected.NetDisplacement, got.NetDisplacement)
+	}
+}
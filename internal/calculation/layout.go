@@ -0,0 +1,164 @@
+package calculation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+var (
+	ErrMarkNotInLayout = errors.New("calculation: reading does not match any mark in the vessel layout")
+	ErrMissingStation  = errors.New("calculation: no readings at draft mark station")
+	ErrSingleSide      = errors.New("calculation: readings on one side only")
+)
+
+type stationReadings struct {
+	port      []float64
+	starboard []float64
+	distances []float64
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func signedDistance(distance units.Length, direction vessel.PPDirection) float64 {
+	if direction == vessel.PPDirectionAft {
+		return -distance.Metres()
+	}
+	return distance.Metres()
+}
+
+func unsignedDistance(signed float64) (units.Length, vessel.PPDirection) {
+	if signed < 0 {
+		return units.Metres(round3(-signed)), vessel.PPDirectionAft
+	}
+	return units.Metres(round3(signed)), vessel.PPDirectionForward
+}
+
+// ResolveMarks reduces readings taken on the vessel's mark layout to port and
+// starboard values at the FWD, MID and AFT stations, and returns a copy of the
+// vessel data with perpendicular distances taken from the marks actually read.
+func ResolveMarks(m types.Marks, v vessel.VesselData) (types.Marks, vessel.VesselData, error) {
+	layout := make(map[string]vessel.DraftMark, len(v.MarkLayout))
+	for _, dm := range v.MarkLayout {
+		layout[dm.Name] = dm
+	}
+
+	stations := map[vessel.MarkStation]*stationReadings{
+		vessel.MarkStationFwd: {},
+		vessel.MarkStationMid: {},
+		vessel.MarkStationAft: {},
+	}
+	for _, r := range m.Readings {
+		dm, ok := layout[r.Name]
+		if !ok {
+			return types.Marks{}, v, fmt.Errorf("%w: %q", ErrMarkNotInLayout, r.Name)
+		}
+		st, ok := stations[dm.Station]
+		if !ok {
+			return types.Marks{}, v, fmt.Errorf("%w: %q", ErrMarkNotInLayout, r.Name)
+		}
+		if dm.Side == vessel.MarkSidePort {
+			st.port = append(st.port, r.Mark.Metres())
+		} else {
+			st.starboard = append(st.starboard, r.Mark.Metres())
+		}
+		st.distances = append(st.distances, signedDistance(dm.Distance, dm.Direction))
+	}
+
+	var listDiff float64
+	listKnown := false
+	for _, name := range []vessel.MarkStation{vessel.MarkStationMid, vessel.MarkStationFwd, vessel.MarkStationAft} {
+		st := stations[name]
+		if len(st.port) > 0 && len(st.starboard) > 0 {
+			listDiff = mean(st.port) - mean(st.starboard)
+			listKnown = true
+			break
+		}
+	}
+
+	sides := make(map[vessel.MarkStation][2]float64, 3)
+	for name, st := range stations {
+		switch {
+		case len(st.port) > 0 && len(st.starboard) > 0:
+			sides[name] = [2]float64{mean(st.port), mean(st.starboard)}
+		case len(st.port) == 0 && len(st.starboard) == 0:
+			continue
+		default:
+			port, starboard, err := singleSide(name, st, v.SingleSideRule, listDiff, listKnown)
+			if err != nil {
+				return types.Marks{}, v, err
+			}
+			sides[name] = [2]float64{port, starboard}
+		}
+	}
+
+	fwd, okFwd := sides[vessel.MarkStationFwd]
+	aft, okAft := sides[vessel.MarkStationAft]
+	if !okFwd {
+		return types.Marks{}, v, fmt.Errorf("%w: %s", ErrMissingStation, vessel.MarkStationFwd)
+	}
+	if !okAft {
+		return types.Marks{}, v, fmt.Errorf("%w: %s", ErrMissingStation, vessel.MarkStationAft)
+	}
+	mid, okMid := sides[vessel.MarkStationMid]
+	if !okMid {
+		mid = [2]float64{(fwd[0] + aft[0]) / 2, (fwd[1] + aft[1]) / 2}
+	}
+
+	v.DistancePPFwd, v.PPFwdDirection = unsignedDistance(mean(stations[vessel.MarkStationFwd].distances))
+	v.DistancePPAft, v.PPAftDirection = unsignedDistance(mean(stations[vessel.MarkStationAft].distances))
+	if okMid {
+		v.DistancePPMid, v.PPMidDirection = unsignedDistance(mean(stations[vessel.MarkStationMid].distances))
+	} else {
+		// The interpolated MID draft lies halfway between the marks read. The
+		// half LBP of the two perpendicular offsets cancels out.
+		v.DistancePPMid, v.PPMidDirection = unsignedDistance(
+			(mean(stations[vessel.MarkStationFwd].distances) + mean(stations[vessel.MarkStationAft].distances)) / 2)
+	}
+
+	return types.Marks{
+		FwdPort:      types.Mark{Value: fwd[0]},
+		FwdStarboard: types.Mark{Value: fwd[1]},
+		MidPort:      types.Mark{Value: mid[0]},
+		MidStarboard: types.Mark{Value: mid[1]},
+		AftPort:      types.Mark{Value: aft[0]},
+		AftStarboard: types.Mark{Value: aft[1]},
+		Readings:     m.Readings,
+	}, v, nil
+}
+
+func singleSide(
+	name vessel.MarkStation, st *stationReadings, rule vessel.SingleSideRule, listDiff float64, listKnown bool,
+) (float64, float64, error) {
+	port := len(st.port) > 0
+	var value float64
+	if port {
+		value = mean(st.port)
+	} else {
+		value = mean(st.starboard)
+	}
+
+	switch rule {
+	case vessel.SingleSideRuleAccept:
+		return value, value, nil
+	case vessel.SingleSideRuleListCorrected:
+		if !listKnown {
+			return 0, 0, fmt.Errorf("%w at %s: list is not measured at any station", ErrSingleSide, name)
+		}
+		if port {
+			return value, value - listDiff, nil
+		}
+		return value + listDiff, value, nil
+	default:
+		return 0, 0, fmt.Errorf("%w at %s", ErrSingleSide, name)
+	}
+}
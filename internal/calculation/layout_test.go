@@ -0,0 +1,165 @@
+package calculation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getSixMarkLayout() []vessel.DraftMark {
+	return []vessel.DraftMark{
+		{Name: "FP", Station: vessel.MarkStationFwd, Side: vessel.MarkSidePort, Distance: 1.400, Direction: vessel.PPDirectionAft},
+		{Name: "FS", Station: vessel.MarkStationFwd, Side: vessel.MarkSideStarboard, Distance: 1.400, Direction: vessel.PPDirectionAft},
+		{Name: "MP", Station: vessel.MarkStationMid, Side: vessel.MarkSidePort, Distance: 0.400, Direction: vessel.PPDirectionAft},
+		{Name: "MS", Station: vessel.MarkStationMid, Side: vessel.MarkSideStarboard, Distance: 0.400, Direction: vessel.PPDirectionAft},
+		{Name: "AP", Station: vessel.MarkStationAft, Side: vessel.MarkSidePort, Distance: 9.950, Direction: vessel.PPDirectionForward},
+		{Name: "AS", Station: vessel.MarkStationAft, Side: vessel.MarkSideStarboard, Distance: 9.950, Direction: vessel.PPDirectionForward},
+	}
+}
+
+func getSixMarkReadings() []types.MarkReading {
+	m := getMarks()
+	return []types.MarkReading{
+		{Name: "FP", Mark: m.FwdPort},
+		{Name: "FS", Mark: m.FwdStarboard},
+		{Name: "MP", Mark: m.MidPort},
+		{Name: "MS", Mark: m.MidStarboard},
+		{Name: "AP", Mark: m.AftPort},
+		{Name: "AS", Mark: m.AftStarboard},
+	}
+}
+
+func TestCalcCondition_Layout(t *testing.T) {
+	s := getConstantSurvey()
+	s.VesselData = vessel.VesselData{LBP: 182.000, VesselType: vessel.VesselTypeMarine, MarkLayout: getSixMarkLayout()}
+	s.InitialDraft.Marks = types.Marks{Readings: getSixMarkReadings()}
+
+	got, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NetDisplacement != 9021.111 {
+		t.Errorf("Expected 9021.111, got %f", got.NetDisplacement)
+	}
+}
+
+func TestResolveMarks_CornerMarksOnly(t *testing.T) {
+	v := vessel.VesselData{MarkLayout: []vessel.DraftMark{
+		{Name: "FP", Station: vessel.MarkStationFwd, Side: vessel.MarkSidePort, Distance: 1.0, Direction: vessel.PPDirectionAft},
+		{Name: "FS", Station: vessel.MarkStationFwd, Side: vessel.MarkSideStarboard, Distance: 1.0, Direction: vessel.PPDirectionAft},
+		{Name: "AP", Station: vessel.MarkStationAft, Side: vessel.MarkSidePort, Distance: 9.0, Direction: vessel.PPDirectionForward},
+		{Name: "AS", Station: vessel.MarkStationAft, Side: vessel.MarkSideStarboard, Distance: 9.0, Direction: vessel.PPDirectionForward},
+	}}
+	m := types.Marks{Readings: []types.MarkReading{
+		{Name: "FP", Mark: types.Mark{Value: 2.00}},
+		{Name: "FS", Mark: types.Mark{Value: 2.10}},
+		{Name: "AP", Mark: types.Mark{Value: 3.00}},
+		{Name: "AS", Mark: types.Mark{Value: 3.10}},
+	}}
+
+	got, resolved, err := ResolveMarks(m, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MidPort.Value != 2.5 || got.MidStarboard.Value != 2.6 {
+		t.Errorf("Mid: expected 2.500/2.600, got %f/%f", got.MidPort.Value, got.MidStarboard.Value)
+	}
+	if resolved.DistancePPMid != 4.0 || resolved.PPMidDirection != vessel.PPDirectionForward {
+		t.Errorf("Mid distance: expected 4.000 F, got %f %s", resolved.DistancePPMid, resolved.PPMidDirection)
+	}
+	if resolved.DistancePPFwd != 1.0 || resolved.PPFwdDirection != vessel.PPDirectionAft {
+		t.Errorf("Fwd distance: expected 1.000 A, got %f %s", resolved.DistancePPFwd, resolved.PPFwdDirection)
+	}
+}
+
+func TestResolveMarks_CornerMarksStraightHull(t *testing.T) {
+	v := vessel.VesselData{LBP: 100.000, VesselType: vessel.VesselTypeMarine, MarkLayout: []vessel.DraftMark{
+		{Name: "FP", Station: vessel.MarkStationFwd, Side: vessel.MarkSidePort, Distance: 1.0, Direction: vessel.PPDirectionAft},
+		{Name: "FS", Station: vessel.MarkStationFwd, Side: vessel.MarkSideStarboard, Distance: 1.0, Direction: vessel.PPDirectionAft},
+		{Name: "AP", Station: vessel.MarkStationAft, Side: vessel.MarkSidePort, Distance: 9.0, Direction: vessel.PPDirectionForward},
+		{Name: "AS", Station: vessel.MarkStationAft, Side: vessel.MarkSideStarboard, Distance: 9.0, Direction: vessel.PPDirectionForward},
+	}}
+	m := types.Marks{Readings: []types.MarkReading{
+		{Name: "FP", Mark: types.Mark{Value: 2.00}},
+		{Name: "FS", Mark: types.Mark{Value: 2.00}},
+		{Name: "AP", Mark: types.Mark{Value: 3.00}},
+		{Name: "AS", Mark: types.Mark{Value: 3.00}},
+	}}
+
+	got, resolved, err := ResolveMarks(m, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Marks 49 m forward and 41 m aft of midships on a straight keel give
+	// 2.544 m at midships, which is the MMC of an unbent hull.
+	mean := MeanDrafts(got)
+	dwk := CalcDraftsWKeel(mean, CalcFullLBPPPCorrections(mean, resolved), resolved)
+	if mmc := CalcMMC(dwk, resolved); mmc != 2.544 {
+		t.Errorf("Expected 2.544, got %f", mmc)
+	}
+}
+
+func TestResolveMarks_TwoMidSets(t *testing.T) {
+	layout := getSixMarkLayout()
+	layout = append(layout,
+		vessel.DraftMark{Name: "MP2", Station: vessel.MarkStationMid, Side: vessel.MarkSidePort, Distance: 1.200, Direction: vessel.PPDirectionForward},
+		vessel.DraftMark{Name: "MS2", Station: vessel.MarkStationMid, Side: vessel.MarkSideStarboard, Distance: 1.200, Direction: vessel.PPDirectionForward},
+	)
+	readings := append(getSixMarkReadings(),
+		types.MarkReading{Name: "MP2", Mark: types.Mark{Value: 4.55}},
+		types.MarkReading{Name: "MS2", Mark: types.Mark{Value: 4.58}},
+	)
+
+	got, resolved, err := ResolveMarks(types.Marks{Readings: readings}, vessel.VesselData{MarkLayout: layout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if round3(got.MidPort.Value) != 4.53 || round3(got.MidStarboard.Value) != 4.56 {
+		t.Errorf("Mid: expected 4.530/4.560, got %f/%f", got.MidPort.Value, got.MidStarboard.Value)
+	}
+	if resolved.DistancePPMid != 0.4 || resolved.PPMidDirection != vessel.PPDirectionForward {
+		t.Errorf("Mid distance: expected 0.400 F, got %f %s", resolved.DistancePPMid, resolved.PPMidDirection)
+	}
+}
+
+func TestResolveMarks_SingleSide(t *testing.T) {
+	var readings []types.MarkReading
+	for _, r := range getSixMarkReadings() {
+		if r.Name != "FS" {
+			readings = append(readings, r)
+		}
+	}
+	m := types.Marks{Readings: readings}
+
+	v := vessel.VesselData{MarkLayout: getSixMarkLayout(), SingleSideRule: vessel.SingleSideRuleReject}
+	if _, _, err := ResolveMarks(m, v); !errors.Is(err, ErrSingleSide) {
+		t.Errorf("Reject: expected %v, got %v", ErrSingleSide, err)
+	}
+
+	v.SingleSideRule = vessel.SingleSideRuleAccept
+	got, _, err := ResolveMarks(m, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FwdStarboard.Value != 3.41 {
+		t.Errorf("Accept: expected 3.410, got %f", got.FwdStarboard.Value)
+	}
+
+	v.SingleSideRule = vessel.SingleSideRuleListCorrected
+	got, _, err = ResolveMarks(m, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if round3(got.FwdStarboard.Value) != 3.44 {
+		t.Errorf("List: expected 3.440, got %f", got.FwdStarboard.Value)
+	}
+}
+
+func TestResolveMarks_UnknownMark(t *testing.T) {
+	m := types.Marks{Readings: []types.MarkReading{{Name: "X", Mark: types.Mark{Value: 1}}}}
+	if _, _, err := ResolveMarks(m, vessel.VesselData{MarkLayout: getSixMarkLayout()}); !errors.Is(err, ErrMarkNotInLayout) {
+		t.Errorf("Expected %v, got %v", ErrMarkNotInLayout, err)
+	}
+}
@@ -0,0 +1,98 @@
+package calculation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+const WarningLoadLineUnchecked = "load_line_unchecked"
+
+var ErrSummerParticulars = errors.New("calculation: summer draft, DWT and TPC are required")
+
+func CalcFWA(displacement, tpc float64) float64 {
+	return round3(displacement / (4 * tpc))
+}
+
+func CalcDWA(fwa, density float64) float64 {
+	return round3(fwa * (1.025 - density) / 0.025)
+}
+
+func allowances(
+	source types.AllowanceSource, draft, displacement, tpc float64, dockDensity units.Density) types.AllowanceResult {
+	fwa := CalcFWA(displacement, tpc)
+	dwa := CalcDWA(fwa, dockDensity.TonnesPerCubicMetre())
+	return types.AllowanceResult{
+		Source:           source,
+		Draft:            draft,
+		Displacement:     displacement,
+		TPC:              tpc,
+		DockDensity:      dockDensity.TonnesPerCubicMetre(),
+		FWA:              fwa,
+		DWA:              dwa,
+		PermissibleDraft: round3(draft + dwa/1000),
+	}
+}
+
+func CalcAllowances(v vessel.VesselData, dockDensity units.Density) (types.AllowanceResult, error) {
+	if v.SummerDraft <= 0 || v.SummerDWT <= 0 || v.SummerTPC <= 0 {
+		return types.AllowanceResult{}, ErrSummerParticulars
+	}
+	if dockDensity <= 0 {
+		return types.AllowanceResult{}, ErrDensity
+	}
+	displacement := round3((v.SummerDWT + v.Lightship).Tonnes())
+	return allowances(types.AllowanceSourceSummer, v.SummerDraft.Metres(), displacement, v.SummerTPC, dockDensity), nil
+}
+
+// CalcAllowancesFromTable reads displacement and TPC at draft, in metres, from
+// a hydrostatic table kept in unit.
+func CalcAllowancesFromTable(
+	draft float64, table []types.HydrostaticRow, unit types.HydrostaticUnit, v vessel.VesselData,
+	dockDensity units.Density,
+) (types.AllowanceResult, error) {
+	if draft <= 0 {
+		draft = v.SummerDraft.Metres()
+	}
+	if dockDensity <= 0 {
+		return types.AllowanceResult{}, ErrDensity
+	}
+	h, err := HydrostaticsAtDraft(draft, types.MetricTable(table, unit), v)
+	if err != nil {
+		return types.AllowanceResult{}, err
+	}
+	return allowances(types.AllowanceSourceHydrostatic, draft, h.Displacement, h.TPC, dockDensity), nil
+}
+
+func CheckLoadLine(final types.ConditionResult, v vessel.VesselData, density units.Density) (types.LoadLineResult, error) {
+	a, err := CalcAllowances(v, density)
+	if err != nil {
+		return types.LoadLineResult{}, err
+	}
+
+	currentDWT := CalcCurrentDWT(final.DisplCorrToDensity, v.Lightship)
+	draftExcess := round3(final.MMC - a.PermissibleDraft)
+	dwtExcess := round3(currentDWT - v.SummerDWT.Tonnes())
+
+	return types.LoadLineResult{
+		Allowances:  a,
+		MMC:         final.MMC,
+		DraftExcess: draftExcess,
+		CurrentDWT:  currentDWT,
+		DWTExcess:   dwtExcess,
+		Overloaded:  draftExcess > 0 || dwtExcess > 0,
+	}, nil
+}
+
+// loadLineUnchecked reports why CheckLoadLine could not run, so that a
+// missing particular does not hide an overloaded vessel.
+func loadLineUnchecked(err error) types.Warning {
+	return types.Warning{
+		Code:     WarningLoadLineUnchecked,
+		Severity: types.SeverityWarning,
+		Message:  fmt.Sprintf("load line not checked: %v", err),
+	}
+}
@@ -0,0 +1,127 @@
+package calculation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getLoadLineVessel() vessel.VesselData {
+	return vessel.VesselData{
+		Lightship:   8390.000,
+		SummerDraft: 12.200,
+		SummerDWT:   56000.000,
+		SummerTPC:   57.600,
+	}
+}
+
+func TestCalcFWA(t *testing.T) {
+	got := CalcFWA(64390.000, 57.600)
+	if got != 279.470 {
+		t.Errorf("Expected 279.470, got %f", got)
+	}
+}
+
+func TestCalcDWA(t *testing.T) {
+	got := CalcDWA(279.470, 1.015)
+	if got != 111.788 {
+		t.Errorf("Expected 111.788, got %f", got)
+	}
+}
+
+func TestCheckLoadLine(t *testing.T) {
+	v := getLoadLineVessel()
+	final := types.ConditionResult{MMC: 12.300, DisplCorrToDensity: 64200.000}
+	got, err := CheckLoadLine(final, v, 1.015)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Allowances.PermissibleDraft != 12.312 {
+		t.Errorf("Permissible draft: expected 12.312, got %f", got.Allowances.PermissibleDraft)
+	}
+	if got.Overloaded {
+		t.Errorf("Expected not overloaded, draft excess %f, DWT excess %f", got.DraftExcess, got.DWTExcess)
+	}
+
+	final.MMC = 12.350
+	got, err = CheckLoadLine(final, v, 1.015)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Overloaded || got.DraftExcess != 0.038 {
+		t.Errorf("Expected overloaded by 0.038 m, got %v %f", got.Overloaded, got.DraftExcess)
+	}
+}
+
+func TestCheckLoadLine_MissingParticulars(t *testing.T) {
+	if _, err := CheckLoadLine(types.ConditionResult{}, vessel.VesselData{}, 1.025); err != ErrSummerParticulars {
+		t.Errorf("Expected %v, got %v", ErrSummerParticulars, err)
+	}
+}
+
+func TestCalcSurvey_LoadLineUnchecked(t *testing.T) {
+	s := getDischargeSurvey()
+	got, err := CalcSurvey(s, types.Tolerances{}, nil, DefaultConstantThreshold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LoadLine != nil || !hasWarning(got.Warnings, WarningLoadLineUnchecked) {
+		t.Errorf("Expected %s warning without summer particulars, got %v", WarningLoadLineUnchecked, got.Warnings)
+	}
+
+	s.VesselData.SummerDraft, s.VesselData.SummerDWT, s.VesselData.SummerTPC = 12.200, 56000.000, 57.600
+	if got, err = CalcSurvey(s, types.Tolerances{}, nil, DefaultConstantThreshold()); err != nil {
+		t.Fatal(err)
+	}
+	if got.LoadLine == nil || hasWarning(got.Warnings, WarningLoadLineUnchecked) {
+		t.Errorf("Expected load line result, got %v", got.Warnings)
+	}
+}
+
+func TestCalcAllowances(t *testing.T) {
+	got, err := CalcAllowances(getLoadLineVessel(), 1.000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FWA != 279.470 || got.DWA != 279.470 {
+		t.Errorf("Expected FWA = DWA = 279.470 in fresh water, got %f %f", got.FWA, got.DWA)
+	}
+	if got.PermissibleDraft != 12.479 {
+		t.Errorf("Permissible draft: expected 12.479, got %f", got.PermissibleDraft)
+	}
+}
+
+func TestCalcAllowancesFromTable(t *testing.T) {
+	v := getPlannerVessel()
+	v.SummerDraft = 10.000
+	got, err := CalcAllowancesFromTable(0, getPlannerTable(), types.HydrostaticUnitMetric, v, 1.015)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Displacement != 40000 || got.TPC != 50 {
+		t.Errorf("Expected displacement 40000 and TPC 50, got %f %f", got.Displacement, got.TPC)
+	}
+	if got.FWA != 200 || got.DWA != 80 {
+		t.Errorf("Expected FWA 200 and DWA 80, got %f %f", got.FWA, got.DWA)
+	}
+	if got.PermissibleDraft != 10.08 {
+		t.Errorf("Permissible draft: expected 10.080, got %f", got.PermissibleDraft)
+	}
+}
+
+func TestCalcAllowancesFromTable_ImperialTable(t *testing.T) {
+	v := getPlannerVessel()
+	v.SummerDraft = 10.000
+	got, err := CalcAllowancesFromTable(0, imperialTable(getPlannerTable()), types.HydrostaticUnitImperial, v, 1.015)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.Displacement-40000) > 0.01 || math.Abs(got.TPC-50) > 0.001 {
+		t.Errorf("Expected displacement 40000 and TPC 50, got %f %f", got.Displacement, got.TPC)
+	}
+	if got.PermissibleDraft != 10.08 {
+		t.Errorf("Permissible draft: expected 10.080, got %f", got.PermissibleDraft)
+	}
+}
@@ -0,0 +1,69 @@
+package calculation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+var (
+	ErrParcelDeclared     = errors.New("calculation: declared parcel quantities are required for pro rata apportionment")
+	ErrParcelIntermediate = errors.New("calculation: intermediate survey quantity is missing")
+	ErrApportionMethod    = errors.New("calculation: unknown apportionment method")
+	ErrParcelRemainder    = errors.New("calculation: intermediate survey quantities exceed the draft survey cargo")
+)
+
+// ApportionParcels splits the draft survey cargo between Bills of Lading. The
+// last parcel takes the remainder so that parcels always sum to the total.
+func ApportionParcels(total float64, parcels []types.Parcel, method types.ApportionMethod) ([]types.ParcelResult, error) {
+	if len(parcels) == 0 {
+		return nil, nil
+	}
+	quantities := make([]float64, len(parcels))
+
+	switch method {
+	case types.ApportionProRata, "":
+		var declared float64
+		for _, p := range parcels {
+			declared += p.Declared.Tonnes()
+		}
+		if declared <= 0 {
+			return nil, ErrParcelDeclared
+		}
+		for i, p := range parcels {
+			quantities[i] = round3(total * p.Declared.Tonnes() / declared)
+		}
+	case types.ApportionIntermediate:
+		for i, p := range parcels[:len(parcels)-1] {
+			if p.Intermediate <= 0 {
+				return nil, fmt.Errorf("%w: B/L %s", ErrParcelIntermediate, p.BLNumber)
+			}
+			quantities[i] = round3(p.Intermediate.Tonnes())
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrApportionMethod, method)
+	}
+
+	var allocated float64
+	for _, q := range quantities[:len(quantities)-1] {
+		allocated += q
+	}
+	remainder := round3(total - allocated)
+	if remainder < 0 {
+		return nil, fmt.Errorf("%w: %.3f MT allocated of %.3f MT", ErrParcelRemainder, round3(allocated), total)
+	}
+	quantities[len(quantities)-1] = remainder
+
+	results := make([]types.ParcelResult, len(parcels))
+	for i, p := range parcels {
+		results[i] = types.ParcelResult{
+			BLNumber:   p.BLNumber,
+			Receiver:   p.Receiver,
+			Declared:   p.Declared.Tonnes(),
+			Quantity:   quantities[i],
+			Difference: round3(quantities[i] - p.Declared.Tonnes()),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,57 @@
+package calculation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func getParcels() []types.Parcel {
+	return []types.Parcel{
+		{BLNumber: "1", Receiver: "A", Declared: 10000, Intermediate: 10050},
+		{BLNumber: "2", Receiver: "B", Declared: 20000, Intermediate: 19900},
+		{BLNumber: "3", Receiver: "C", Declared: 3000},
+	}
+}
+
+func TestApportionParcels_ProRata(t *testing.T) {
+	got, err := ApportionParcels(33100, getParcels(), types.ApportionProRata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{10030.303, 20060.606, 3009.091}
+	var sum float64
+	for i, p := range got {
+		if p.Quantity != expected[i] {
+			t.Errorf("B/L %s: expected %f, got %f", p.BLNumber, expected[i], p.Quantity)
+		}
+		sum += p.Quantity
+	}
+	if round3(sum) != 33100 {
+		t.Errorf("Sum: expected 33100, got %f", sum)
+	}
+	if got[0].Difference != 30.303 {
+		t.Errorf("Difference: expected 30.303, got %f", got[0].Difference)
+	}
+}
+
+func TestApportionParcels_Intermediate(t *testing.T) {
+	got, err := ApportionParcels(33100, getParcels(), types.ApportionIntermediate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Quantity != 10050 || got[1].Quantity != 19900 || got[2].Quantity != 3150 {
+		t.Errorf("Expected 10050/19900/3150, got %f/%f/%f", got[0].Quantity, got[1].Quantity, got[2].Quantity)
+	}
+
+	parcels := getParcels()
+	parcels[1].Intermediate = 0
+	if _, err := ApportionParcels(33100, parcels, types.ApportionIntermediate); !errors.Is(err, ErrParcelIntermediate) {
+		t.Errorf("Expected %v, got %v", ErrParcelIntermediate, err)
+	}
+
+	if _, err := ApportionParcels(25000, getParcels(), types.ApportionIntermediate); !errors.Is(err, ErrParcelRemainder) {
+		t.Errorf("Expected %v, got %v", ErrParcelRemainder, err)
+	}
+}
@@ -0,0 +1,155 @@
+package calculation
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+var (
+	ErrOutsideTable  = errors.New("calculation: value is outside the hydrostatic table")
+	ErrNoConvergence = errors.New("calculation: draft prediction did not converge")
+	ErrDensity       = errors.New("calculation: density is not set")
+)
+
+const (
+	plannerMaxIterations = 50
+	plannerTolerance     = 0.0005
+)
+
+func sortedTable(table []types.HydrostaticRow) []types.HydrostaticRow {
+	sorted := make([]types.HydrostaticRow, len(table))
+	copy(sorted, table)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Draft < sorted[j].Draft })
+	return sorted
+}
+
+func bracketByDraft(draft float64, table []types.HydrostaticRow) ([]types.HydrostaticRow, error) {
+	for i := 1; i < len(table); i++ {
+		if draft >= table[i-1].Draft && draft <= table[i].Draft {
+			return table[i-1 : i+1], nil
+		}
+	}
+	return nil, ErrOutsideTable
+}
+
+// DraftForDisplacement interpolates the draft at displacement in a metric
+// hydrostatic table.
+func DraftForDisplacement(displacement float64, table []types.HydrostaticRow) (float64, error) {
+	sorted := sortedTable(table)
+	for i := 1; i < len(sorted); i++ {
+		lower, upper := sorted[i-1], sorted[i]
+		if displacement >= lower.Displacement && displacement <= upper.Displacement {
+			return Interpolate(displacement, lower.Displacement, lower.Draft, upper.Displacement, upper.Draft), nil
+		}
+	}
+	return 0, ErrOutsideTable
+}
+
+// HydrostaticsAtDraft interpolates a metric hydrostatic table at draft.
+func HydrostaticsAtDraft(draft float64, table []types.HydrostaticRow, v vessel.VesselData) (types.Hydrostatics, error) {
+	rows, err := bracketByDraft(draft, sortedTable(table))
+	if err != nil {
+		return types.Hydrostatics{}, err
+	}
+	return CalcHydrostatics(draft, rows, v), nil
+}
+
+func trimmedDrafts(mmc, trim float64) types.DraftsWKeel {
+	return types.DraftsWKeel{
+		FwdDraftWKeel: round3(mmc - trim/2),
+		MidDraftWKeel: mmc,
+		AftDraftWKeel: round3(mmc + trim/2),
+	}
+}
+
+func seaWaterDisplacementAtDraft(
+	mmc, trim float64, table []types.HydrostaticRow, v vessel.VesselData) (float64, error) {
+	h, err := HydrostaticsAtDraft(mmc, table, v)
+	if err != nil {
+		return 0, err
+	}
+	ftc := CalcFirstTrimCorrection(trimmedDrafts(mmc, trim), h.TPC, h.LCF, v.LBP.Metres())
+	return round3(h.Displacement + ftc), nil
+}
+
+// PlanLoading predicts the drafts after loading the rest of the planned cargo.
+// The hydrostatic table is kept in unit and converted to metric first.
+func PlanLoading(
+	current types.ConditionResult, plan types.LoadingPlan,
+	table []types.HydrostaticRow, unit types.HydrostaticUnit, v vessel.VesselData,
+) (types.LoadingPrediction, error) {
+	table = types.MetricTable(table, unit)
+	if len(table) < 2 {
+		return types.LoadingPrediction{}, ErrHydrostaticRows
+	}
+	if v.LBP <= 0 {
+		return types.LoadingPrediction{}, ErrLBP
+	}
+	if plan.Density <= 0 {
+		return types.LoadingPrediction{}, ErrDensity
+	}
+	if err := units.ValidateWaterDensity(plan.Density); err != nil {
+		return types.LoadingPrediction{}, err
+	}
+	density, trim := plan.Density.TonnesPerCubicMetre(), plan.Trim.Metres()
+	deductiblesChange := plan.DeductiblesChange.Tonnes()
+
+	remaining := round3((plan.PlannedCargo - plan.CargoOnBoard).Tonnes())
+	displacement := round3(current.DisplCorrToDensity + remaining + deductiblesChange)
+	seaWater := round3(displacement * 1.025 / density)
+
+	mmc, err := DraftForDisplacement(seaWater, table)
+	if err != nil {
+		return types.LoadingPrediction{}, err
+	}
+
+	var ftc float64
+	converged := false
+	for range plannerMaxIterations {
+		h, err := HydrostaticsAtDraft(mmc, table, v)
+		if err != nil {
+			return types.LoadingPrediction{}, err
+		}
+		ftc = CalcFirstTrimCorrection(trimmedDrafts(mmc, trim), h.TPC, h.LCF, v.LBP.Metres())
+		next, err := DraftForDisplacement(round3(seaWater-ftc), table)
+		if err != nil {
+			return types.LoadingPrediction{}, err
+		}
+		if math.Abs(next-mmc) < plannerTolerance {
+			mmc = next
+			converged = true
+			break
+		}
+		mmc = next
+	}
+	if !converged {
+		return types.LoadingPrediction{}, ErrNoConvergence
+	}
+
+	drafts := trimmedDrafts(mmc, trim)
+	prediction := types.LoadingPrediction{
+		CargoRemaining:      remaining,
+		Displacement:        displacement,
+		MMC:                 mmc,
+		DraftFwd:            drafts.FwdDraftWKeel,
+		DraftMid:            drafts.MidDraftWKeel,
+		DraftAft:            drafts.AftDraftWKeel,
+		FirstTrimCorrection: ftc,
+	}
+
+	if plan.TargetDraft > 0 {
+		target, err := seaWaterDisplacementAtDraft(plan.TargetDraft.Metres(), trim, table, v)
+		if err != nil {
+			return types.LoadingPrediction{}, err
+		}
+		targetDisplacement := round3(target * density / 1.025)
+		prediction.CargoToTargetDraft = round3(targetDisplacement - current.DisplCorrToDensity - deductiblesChange)
+	}
+
+	return prediction, nil
+}
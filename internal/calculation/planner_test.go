@@ -0,0 +1,123 @@
+package calculation
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getPlannerVessel() vessel.VesselData {
+	return vessel.VesselData{
+		LBP:        200.000,
+		VesselType: vessel.VesselTypeMarine,
+	}
+}
+
+func getPlannerTable() []types.HydrostaticRow {
+	var table []types.HydrostaticRow
+	for d := 12; d >= 4; d-- {
+		table = append(table, types.HydrostaticRow{
+			Draft:        float64(d),
+			Displacement: 10000 + 5000*float64(d-4),
+			TPC:          50,
+			LCF:          2,
+			LCFDirection: types.LCFDirectionForward,
+		})
+	}
+	return table
+}
+
+func TestDraftForDisplacement(t *testing.T) {
+	got, err := DraftForDisplacement(32500, getPlannerTable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 8.5 {
+		t.Errorf("Expected 8.500, got %f", got)
+	}
+	if _, err := DraftForDisplacement(60000, getPlannerTable()); err != ErrOutsideTable {
+		t.Errorf("Expected %v, got %v", ErrOutsideTable, err)
+	}
+}
+
+func TestPlanLoading_EvenKeel(t *testing.T) {
+	current := types.ConditionResult{DisplCorrToDensity: 20000}
+	plan := types.LoadingPlan{
+		PlannedCargo: 12000,
+		CargoOnBoard: 2000,
+		Density:      1.025,
+		TargetDraft:  9.0,
+	}
+	got, err := PlanLoading(current, plan, getPlannerTable(), types.HydrostaticUnitMetric, getPlannerVessel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CargoRemaining != 10000 {
+		t.Errorf("Remaining: expected 10000, got %f", got.CargoRemaining)
+	}
+	if got.MMC != 8.0 || got.DraftFwd != 8.0 || got.DraftAft != 8.0 {
+		t.Errorf("Drafts: expected 8.000, got F %f M %f A %f", got.DraftFwd, got.MMC, got.DraftAft)
+	}
+	if got.CargoToTargetDraft != 15000 {
+		t.Errorf("To target draft: expected 15000, got %f", got.CargoToTargetDraft)
+	}
+}
+
+func TestPlanLoading_Trim(t *testing.T) {
+	current := types.ConditionResult{DisplCorrToDensity: 20000}
+	plan := types.LoadingPlan{
+		PlannedCargo: 10000,
+		Density:      1.025,
+		Trim:         1.0,
+	}
+	got, err := PlanLoading(current, plan, getPlannerTable(), types.HydrostaticUnitMetric, getPlannerVessel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FirstTrimCorrection != -50 {
+		t.Errorf("FTC: expected -50, got %f", got.FirstTrimCorrection)
+	}
+	if got.MMC != 8.01 {
+		t.Errorf("MMC: expected 8.010, got %f", got.MMC)
+	}
+	if got.DraftFwd != 7.51 || got.DraftAft != 8.51 {
+		t.Errorf("Drafts: expected F 7.510 A 8.510, got F %f A %f", got.DraftFwd, got.DraftAft)
+	}
+}
+
+func TestPlanLoading_ImperialTable(t *testing.T) {
+	current := types.ConditionResult{DisplCorrToDensity: 20000}
+	plan := types.LoadingPlan{
+		PlannedCargo: 10000,
+		Density:      1.025,
+		Trim:         1.0,
+		TargetDraft:  9.0,
+	}
+	expected, err := PlanLoading(current, plan, getPlannerTable(), types.HydrostaticUnitMetric, getPlannerVessel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := PlanLoading(current, plan, imperialTable(getPlannerTable()), types.HydrostaticUnitImperial, getPlannerVessel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(expected.MMC-got.MMC) > 0.001 {
+		t.Errorf("MMC: expected %f, got %f", expected.MMC, got.MMC)
+	}
+	if math.Abs(expected.CargoToTargetDraft-got.CargoToTargetDraft) > 0.01 {
+		t.Errorf("To target draft: expected %f, got %f", expected.CargoToTargetDraft, got.CargoToTargetDraft)
+	}
+}
+
+func TestPlanLoading_DensityInKilograms(t *testing.T) {
+	plan := types.LoadingPlan{PlannedCargo: 10000, Density: 1025}
+	_, err := PlanLoading(types.ConditionResult{DisplCorrToDensity: 20000}, plan,
+		getPlannerTable(), types.HydrostaticUnitMetric, getPlannerVessel())
+	if !errors.Is(err, units.ErrOutOfRange) {
+		t.Errorf("Expected %v, got %v", units.ErrOutOfRange, err)
+	}
+}
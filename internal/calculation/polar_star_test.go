@@ -143,7 +143,7 @@ func TestPolarStar_TrimNoList_FirstTrimCorrection(t *testing.T) {
 	mmc := CalcMMC(draftsWKeel, vesselData)
 	hr := getPolarStarTrimNoListHydrostaticRows()
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
-	got := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
+	got := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
 
 	if got != -487.653 {
 		t.Errorf("1st trim: expected -487.653, got %f", got)
@@ -157,7 +157,7 @@ func TestPolarStar_TrimNoList_SecondTrimCorrection(t *testing.T) {
 	ppCorrections := CalcFullLBPPPCorrections(meanDraft, vesselData)
 	draftsWKeel := CalcDraftsWKeel(meanDraft, ppCorrections, vesselData)
 	mtcRows := getPolarStarTrimNoListMTCRows()
-	got := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	got := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 
 	if got != 59.088 {
 		t.Errorf("2nd trim: expected 59.088, got %f", got)
@@ -183,8 +183,8 @@ func TestPolarStar_TrimNoList_DensityCorrection(t *testing.T) {
 	hr := getPolarStarTrimNoListHydrostaticRows()
 	hydrostatics := CalcHydrostatics(mmc, hr, vesselData)
 	mtcRows := getPolarStarTrimNoListMTCRows()
-	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
-	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, mtcRows, vesselData.LBP.Metres())
 	listCorrection := CalcListCorrection(marks, 0, 0)
 	got := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorrection, 1.017)
 
@@ -308,7 +308,7 @@ func TestPolarStar_TrimList_FirstTrimCorrection(t *testing.T) {
 	draftsWKeel := CalcDraftsWKeel(meanDraft, CalcFullLBPPPCorrections(meanDraft, vesselData), vesselData)
 	mmc := CalcMMC(draftsWKeel, vesselData)
 	hydrostatics := CalcHydrostatics(mmc, getPolarStarTrimListHydrostaticRows(), vesselData)
-	got := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
+	got := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
 	if got != -481.481 {
 		t.Errorf("1st trim: expected -481.481, got %f", got)
 	}
@@ -318,7 +318,7 @@ func TestPolarStar_TrimList_SecondTrimCorrection(t *testing.T) {
 	vesselData := getPolarStarTrimListVessel()
 	meanDraft := MeanDrafts(getPolarStarTrimListMarks())
 	draftsWKeel := CalcDraftsWKeel(meanDraft, CalcFullLBPPPCorrections(meanDraft, vesselData), vesselData)
-	got := CalcSecondTrimCorrection(draftsWKeel, getPolarStarTrimListMTCRows(), vesselData.LBP)
+	got := CalcSecondTrimCorrection(draftsWKeel, getPolarStarTrimListMTCRows(), vesselData.LBP.Metres())
 	if got != 57.622 {
 		t.Errorf("2nd trim: expected 57.622, got %f", got)
 	}
@@ -338,8 +338,8 @@ func TestPolarStar_TrimList_DensityCorrection(t *testing.T) {
 	draftsWKeel := CalcDraftsWKeel(meanDraft, CalcFullLBPPPCorrections(meanDraft, vesselData), vesselData)
 	mmc := CalcMMC(draftsWKeel, vesselData)
 	hydrostatics := CalcHydrostatics(mmc, getPolarStarTrimListHydrostaticRows(), vesselData)
-	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP)
-	secondTrim := CalcSecondTrimCorrection(draftsWKeel, getPolarStarTrimListMTCRows(), vesselData.LBP)
+	firstTrim := CalcFirstTrimCorrection(draftsWKeel, hydrostatics.TPC, hydrostatics.LCF, vesselData.LBP.Metres())
+	secondTrim := CalcSecondTrimCorrection(draftsWKeel, getPolarStarTrimListMTCRows(), vesselData.LBP.Metres())
 	listCorr := CalcListCorrection(marks, 45.212, 45.129)
 	got := CalcDensityCorrection(hydrostatics.Displacement, firstTrim, secondTrim, listCorr, 1.017)
 	if got != -146.234 {
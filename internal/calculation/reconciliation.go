@@ -0,0 +1,73 @@
+package calculation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+// LoadTolerances reads reconciliation tolerances from a JSON file such as
+// configs/tolerances.json.
+func LoadTolerances(path string) (types.Tolerances, error) {
+	var tolerances types.Tolerances
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tolerances, err
+	}
+	if err = json.Unmarshal(data, &tolerances); err != nil {
+		return tolerances, fmt.Errorf("calculation: %s: %w", path, err)
+	}
+	return tolerances, nil
+}
+
+func ClassifyDifference(percent float64, tol types.Tolerance) types.ReconciliationStatus {
+	p := math.Abs(percent)
+	if tol.LimitPercent > 0 && p > tol.LimitPercent {
+		return types.ReconciliationExceeded
+	}
+	if tol.WarningPercent > 0 && p > tol.WarningPercent {
+		return types.ReconciliationWarning
+	}
+	return types.ReconciliationWithin
+}
+
+func CalcFigureDifference(
+	cargo float64, source types.FigureSource, declared float64, tol types.Tolerance) types.FigureDifference {
+	difference := round3(cargo - declared)
+	var percent float64
+	if declared != 0 {
+		percent = round3(difference * 100 / declared)
+	}
+
+	return types.FigureDifference{
+		Source:     source,
+		Declared:   declared,
+		Difference: difference,
+		Percent:    percent,
+		Status:     ClassifyDifference(percent, tol),
+	}
+}
+
+func Reconcile(cargo float64, s *types.Survey, tolerances types.Tolerances) []types.FigureDifference {
+	tol := tolerances.For(s.CargoOperation.Cargo, s.Job.Principal)
+	figures := []struct {
+		source   types.FigureSource
+		declared float64
+	}{
+		{types.FigureSourceBillOfLading, s.CargoOperation.Figures.BillOfLading.Tonnes()},
+		{types.FigureSourceShoreScale, s.CargoOperation.Figures.ShoreScale.Tonnes()},
+		{types.FigureSourceShipFigure, s.FinalDraft.CargoDeclared.Tonnes()},
+	}
+
+	var differences []types.FigureDifference
+	for _, f := range figures {
+		if f.declared == 0 {
+			continue
+		}
+		differences = append(differences, CalcFigureDifference(cargo, f.source, f.declared, tol))
+	}
+	return differences
+}
@@ -0,0 +1,90 @@
+package calculation
+
+import (
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func getTolerances() types.Tolerances {
+	return types.Tolerances{
+		Default: types.Tolerance{WarningPercent: 0.3, LimitPercent: 0.5},
+		ByCargo: map[string]types.Tolerance{
+			"Coal": {WarningPercent: 0.5, LimitPercent: 1.0},
+		},
+		ByPrincipal: map[string]types.Tolerance{
+			"Strict": {WarningPercent: 0.1, LimitPercent: 0.2},
+		},
+	}
+}
+
+func TestTolerances_For(t *testing.T) {
+	tol := getTolerances()
+	if got := tol.For("Coal", "Strict"); got.LimitPercent != 0.2 {
+		t.Errorf("Principal: expected 0.2, got %f", got.LimitPercent)
+	}
+	if got := tol.For("Coal", "Other"); got.LimitPercent != 1.0 {
+		t.Errorf("Cargo: expected 1.0, got %f", got.LimitPercent)
+	}
+	if got := tol.For("Grain", "Other"); got.LimitPercent != 0.5 {
+		t.Errorf("Default: expected 0.5, got %f", got.LimitPercent)
+	}
+}
+
+func TestLoadTolerances(t *testing.T) {
+	tolerances, err := LoadTolerances("../../configs/tolerances.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := types.Tolerance{WarningPercent: 0.3, LimitPercent: 0.5}
+	if got := tolerances.For("Wheat", "Cargill"); got != expected {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if _, err = LoadTolerances("testdata/missing.json"); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func TestCalcFigureDifference(t *testing.T) {
+	tol := types.Tolerance{WarningPercent: 0.3, LimitPercent: 0.5}
+	tests := []struct {
+		cargo    float64
+		declared float64
+		percent  float64
+		status   types.ReconciliationStatus
+	}{
+		{40000, 40040, -0.1, types.ReconciliationWithin},
+		{40000, 39840, 0.402, types.ReconciliationWarning},
+		{40000, 40400, -0.99, types.ReconciliationExceeded},
+	}
+	for _, tt := range tests {
+		got := CalcFigureDifference(tt.cargo, types.FigureSourceBillOfLading, tt.declared, tol)
+		if got.Percent != tt.percent {
+			t.Errorf("Percent: expected %f, got %f", tt.percent, got.Percent)
+		}
+		if got.Status != tt.status {
+			t.Errorf("Status: expected %s, got %s", tt.status, got.Status)
+		}
+	}
+}
+
+func TestReconcile_SkipsMissingFigures(t *testing.T) {
+	s := &types.Survey{
+		CargoOperation: types.CargoOperation{
+			Cargo:   "Coal",
+			Figures: types.CargoFigures{BillOfLading: 40100},
+		},
+		FinalDraft: types.FinalDraft{CargoDeclared: 39950},
+	}
+	got := Reconcile(40000, s, getTolerances())
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 differences, got %d", len(got))
+	}
+	if got[0].Source != types.FigureSourceBillOfLading || got[0].Difference != -100 {
+		t.Errorf("Expected B/L difference -100, got %s %f", got[0].Source, got[0].Difference)
+	}
+	if got[1].Source != types.FigureSourceShipFigure || got[1].Difference != 50 {
+		t.Errorf("Expected ship difference 50, got %s %f", got[1].Source, got[1].Difference)
+	}
+}
@@ -0,0 +1,34 @@
+package calculation
+
+import (
+	"fmt"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+const WarningIceReadings = "ice_readings"
+
+var iceSeverity = map[types.IceCondition]types.Severity{
+	types.IceCondition015To020: types.SeverityWarning,
+	types.IceCondition020To030: types.SeverityWarning,
+	types.IceCondition030To040: types.SeverityWarning,
+	types.IceCondition040To060: types.SeverityError,
+	types.IceConditionOver060:  types.SeverityError,
+}
+
+func CheckSeaCondition(condition string, sc types.SeaCondition) []types.Warning {
+	if sc.Type != types.SeaConditionTypeIce {
+		return nil
+	}
+	severity, ok := iceSeverity[sc.Ice]
+	if !ok {
+		return nil
+	}
+	return []types.Warning{{
+		Code:     WarningIceReadings,
+		Severity: severity,
+		Message: fmt.Sprintf(
+			"%s condition: ice %s, draft readings accuracy is compromised; account for ice and snow accretion",
+			condition, sc.Ice),
+	}}
+}
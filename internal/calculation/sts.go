@@ -0,0 +1,54 @@
+package calculation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+var ErrSTSLink = errors.New("calculation: surveys are not linked as STS mother and daughter")
+
+func checkSTSLink(s *types.Survey, role types.STSRole, counterpartID string) error {
+	if s.STS == nil || s.STS.Role != role || s.STS.CounterpartID != counterpartID {
+		return fmt.Errorf("%w: %s survey %q", ErrSTSLink, role, s.ID)
+	}
+	return nil
+}
+
+// CalcTransfer compares the cargo of linked STS mother and daughter surveys.
+// Their constants are not checked against vessel history.
+func CalcTransfer(mother, daughter *types.Survey, tolerances types.Tolerances) (types.TransferResult, error) {
+	if err := checkSTSLink(mother, types.STSRoleMother, daughter.ID); err != nil {
+		return types.TransferResult{}, err
+	}
+	if err := checkSTSLink(daughter, types.STSRoleDaughter, mother.ID); err != nil {
+		return types.TransferResult{}, err
+	}
+
+	motherResult, err := CalcSurvey(mother, tolerances, nil, types.ConstantThreshold{})
+	if err != nil {
+		return types.TransferResult{}, fmt.Errorf("mother vessel: %w", err)
+	}
+	daughterResult, err := CalcSurvey(daughter, tolerances, nil, types.ConstantThreshold{})
+	if err != nil {
+		return types.TransferResult{}, fmt.Errorf("daughter vessel: %w", err)
+	}
+
+	difference := CalcFigureDifference(
+		daughterResult.Cargo,
+		types.FigureSourceShipFigure,
+		motherResult.Cargo,
+		tolerances.For(mother.CargoOperation.Cargo, mother.Job.Principal),
+	)
+
+	return types.TransferResult{
+		Mother:             motherResult,
+		Daughter:           daughterResult,
+		MotherCargo:        motherResult.Cargo,
+		DaughterCargo:      daughterResult.Cargo,
+		Discrepancy:        difference.Difference,
+		DiscrepancyPercent: difference.Percent,
+		Status:             difference.Status,
+	}, nil
+}
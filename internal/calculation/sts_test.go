@@ -0,0 +1,50 @@
+package calculation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getSTSSurvey(id, counterpartID string, role types.STSRole, initialDraft, finalDraft float64) *types.Survey {
+	unit := getConvoyUnit(id, initialDraft, finalDraft)
+	unit.VesselData.VesselType = vessel.VesselTypeMarine
+	return &types.Survey{
+		ID:           id,
+		Kind:         types.SurveyKindSTS,
+		VesselData:   unit.VesselData,
+		InitialDraft: unit.InitialDraft,
+		FinalDraft:   unit.FinalDraft,
+		STS:          &types.STSLink{Role: role, CounterpartID: counterpartID},
+	}
+}
+
+func TestCalcTransfer(t *testing.T) {
+	mother := getSTSSurvey("mother", "daughter", types.STSRoleMother, 2.5, 1.5)
+	daughter := getSTSSurvey("daughter", "mother", types.STSRoleDaughter, 1.5, 2.49)
+
+	got, err := CalcTransfer(mother, daughter, getTolerances())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MotherCargo != 1000 || got.DaughterCargo != 990 {
+		t.Errorf("Expected 1000 and 990, got %f and %f", got.MotherCargo, got.DaughterCargo)
+	}
+	if got.Discrepancy != -10 || got.DiscrepancyPercent != -1 {
+		t.Errorf("Expected -10 MT / -1%%, got %f / %f", got.Discrepancy, got.DiscrepancyPercent)
+	}
+	if got.Status != types.ReconciliationExceeded {
+		t.Errorf("Expected %s, got %s", types.ReconciliationExceeded, got.Status)
+	}
+}
+
+func TestCalcTransfer_NotLinked(t *testing.T) {
+	mother := getSTSSurvey("mother", "other", types.STSRoleMother, 2.5, 1.5)
+	daughter := getSTSSurvey("daughter", "mother", types.STSRoleDaughter, 1.5, 2.5)
+
+	if _, err := CalcTransfer(mother, daughter, getTolerances()); !errors.Is(err, ErrSTSLink) {
+		t.Errorf("Expected %v, got %v", ErrSTSLink, err)
+	}
+}
@@ -0,0 +1,57 @@
+package calculation
+
+import (
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+// CalcSurvey calculates a draft survey. The constant is taken from the light
+// condition, as in ConstantHistory, and checked against the vessel's history.
+func CalcSurvey(
+	s *types.Survey, tolerances types.Tolerances, history []types.ConstantRecord, threshold types.ConstantThreshold,
+) (types.SurveyResult, error) {
+	initial, err := CalcCondition(s.InitialDraft.Condition(), s.VesselData)
+	if err != nil {
+		return types.SurveyResult{}, err
+	}
+	final, err := CalcCondition(s.FinalDraft.Condition(), s.VesselData)
+	if err != nil {
+		return types.SurveyResult{}, err
+	}
+
+	cargo := CalcCargoWeight(initial.NetDisplacement, final.NetDisplacement)
+	light, _ := lightCondition(s, initial, final)
+	constant := CalcConstant(light.NetDisplacement, s.VesselData.Lightship)
+
+	result := types.SurveyResult{
+		Initial:              initial,
+		Final:                final,
+		Cargo:                cargo,
+		Constant:             constant,
+		ConstantDeclaredDiff: round3(constant - s.InitialDraft.ConstantDeclared.Tonnes()),
+		Reconciliation:       Reconcile(cargo, s, tolerances),
+		DeductiblesChange:    CalcDeductiblesChange(s.InitialDraft, s.FinalDraft),
+	}
+
+	result.Warnings = append(result.Warnings, CheckSeaCondition("initial", s.InitialDraft.SeaCondition)...)
+	result.Warnings = append(result.Warnings, CheckSeaCondition("final", s.FinalDraft.SeaCondition)...)
+	if w := CheckConstantAnomaly(constant, ConstantStatistics(history), threshold); w != nil {
+		result.Warnings = append(result.Warnings, *w)
+	}
+
+	if len(s.CargoOperation.Parcels) > 0 {
+		parcels, err := ApportionParcels(cargo, s.CargoOperation.Parcels, s.CargoOperation.ApportionMethod)
+		if err != nil {
+			return types.SurveyResult{}, err
+		}
+		result.Parcels = parcels
+	}
+
+	loadLine, err := CheckLoadLine(final, s.VesselData, s.FinalDraft.Density)
+	if err != nil {
+		result.Warnings = append(result.Warnings, loadLineUnchecked(err))
+	} else {
+		result.LoadLine = &loadLine
+	}
+
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func Allowances(v vessel.VesselData, a types.AllowanceResult, system units.System) Layout {
+	f := formatter{units.PreferencesFor(system)}
+	return Layout{
+		Title: "Fresh Water / Dock Water Allowance",
+		Sections: []Section{
+			{
+				Title: "Vessel",
+				Rows: []Row{
+					{Label: "Name", Value: v.Name},
+					{Label: "IMO", Value: v.IMO},
+				},
+			},
+			allowanceSection(f, a),
+		},
+	}
+}
+
+func allowanceSection(f formatter, a types.AllowanceResult) Section {
+	return Section{
+		Title: "Allowances",
+		Rows: []Row{
+			{Label: "Source", Value: string(a.Source)},
+			f.length("Draft", a.Draft),
+			f.mass("Displacement", a.Displacement),
+			f.immersion(a.TPC),
+			f.density("Dock water density", a.DockDensity),
+			f.allowance("FWA", a.FWA),
+			f.allowance("DWA", a.DWA),
+			f.length("Permissible draft in dock water", a.PermissibleDraft),
+		},
+	}
+}
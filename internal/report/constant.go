@@ -0,0 +1,47 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func ConstantSurvey(s *types.Survey, r types.ConstantResult) Layout {
+	f := newFormatter(s)
+	constant := Section{
+		Title: "Constant",
+		Rows: []Row{
+			f.mass("Lightship", s.VesselData.Lightship.Tonnes()),
+			f.mass("Constant calculated", r.Constant),
+			f.mass("Constant declared", r.Declared),
+			f.mass("Difference to declared", r.DeclaredDiff),
+		},
+	}
+	if r.History.Count > 0 {
+		constant.Rows = append(constant.Rows,
+			Row{Label: "Previous surveys", Value: strconv.Itoa(r.History.Count)},
+			f.mass("Historical mean", r.History.Mean),
+			f.mass("Historical standard deviation", r.History.StdDev),
+			f.mass("Historical minimum", r.History.Min),
+			f.mass("Historical maximum", r.History.Max),
+			f.mass("Trend per year", r.History.TrendPerYr),
+			f.mass("Difference to historical mean", r.HistoryDiff),
+		)
+	}
+
+	sections := []Section{
+		vesselSection(f, s),
+		jobSection(s),
+		marksSection("Draft readings", s.InitialDraft.Marks),
+		conditionSection(f, "Condition", r.Condition),
+		constant,
+	}
+	if len(r.Warnings) > 0 {
+		sections = append(sections, warningsSection(r.Warnings))
+	}
+
+	return Layout{
+		Title:    "Lightship / Constant Verification",
+		Sections: sections,
+	}
+}
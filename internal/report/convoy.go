@@ -0,0 +1,37 @@
+package report
+
+import (
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func ConvoySurvey(s *types.Survey, r types.ConvoyResult) Layout {
+	f := newFormatter(s)
+	breakdown := Section{Title: "Cargo by barge"}
+	for _, u := range r.Units {
+		breakdown.Rows = append(breakdown.Rows, f.mass(u.Name, u.Cargo))
+	}
+	breakdown.Rows = append(breakdown.Rows, f.mass("Total cargo by draft survey", r.TotalCargo))
+
+	sections := []Section{
+		vesselSection(f, s),
+		jobSection(s),
+	}
+	for i, u := range r.Units {
+		unit := s.Convoy[i]
+		sections = append(sections,
+			marksSection(u.Name+" initial draft readings", unit.InitialDraft.Marks),
+			conditionSection(f, u.Name+" initial condition", u.Initial),
+			marksSection(u.Name+" final draft readings", unit.FinalDraft.Marks),
+			conditionSection(f, u.Name+" final condition", u.Final),
+		)
+	}
+	sections = append(sections, breakdown)
+	if len(r.Reconciliation) > 0 {
+		sections = append(sections, reconciliationSection(f, r.Reconciliation))
+	}
+
+	return Layout{
+		Title:    "Convoy Draft Survey Report",
+		Sections: sections,
+	}
+}
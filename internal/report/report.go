@@ -0,0 +1,147 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+type Row struct {
+	Label string
+	Value string
+}
+
+type Section struct {
+	Title string
+	Rows  []Row
+}
+
+type Layout struct {
+	Title    string
+	Sections []Section
+}
+
+type formatter struct {
+	units.Preferences
+}
+
+func newFormatter(s *types.Survey) formatter {
+	return formatter{units.PreferencesFor(s.Job.Units)}
+}
+
+func (f formatter) length(label string, v float64) Row {
+	return Row{Label: label + ", " + string(f.Length), Value: f.FormatLength(units.Metres(v))}
+}
+
+func (f formatter) mass(label string, v float64) Row {
+	return Row{Label: label + ", " + string(f.Mass), Value: f.FormatMass(units.Tonnes(v))}
+}
+
+func (f formatter) density(label string, v float64) Row {
+	return Row{Label: label + ", " + string(f.Density), Value: f.FormatDensity(units.Density(v))}
+}
+
+// immersion reports tonnes per centimetre immersion, or long tons per inch
+// when the report is in imperial units.
+func (f formatter) immersion(tpc float64) Row {
+	if f.Length == units.Foot {
+		return Row{Label: "TPI, LT/in", Value: num(types.TPCToTPI(tpc))}
+	}
+	return Row{Label: "TPC, MT/cm", Value: num(tpc)}
+}
+
+// allowance reports a freshwater or dock water allowance given in
+// millimetres, converted to inches when the report is in imperial units.
+func (f formatter) allowance(label string, mm float64) Row {
+	if f.Length == units.Foot {
+		in, _ := units.Millimetres(mm).In(units.Inch)
+		return Row{Label: label + ", in", Value: num(in)}
+	}
+	return Row{Label: label + ", mm", Value: num(mm)}
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+func vesselSection(f formatter, s *types.Survey) Section {
+	v := s.VesselData
+	return Section{
+		Title: "Vessel",
+		Rows: []Row{
+			{Label: "Name", Value: v.Name},
+			{Label: "IMO", Value: v.IMO},
+			{Label: "Flag", Value: v.Flag},
+			f.length("LBP", v.LBP.Metres()),
+			f.mass("Lightship", v.Lightship.Tonnes()),
+		},
+	}
+}
+
+func jobSection(s *types.Survey) Section {
+	return Section{
+		Title: "Job",
+		Rows: []Row{
+			{Label: "Job number", Value: strconv.Itoa(s.Job.JobNumber)},
+			{Label: "DS number", Value: strconv.Itoa(s.Job.DSNumber)},
+			{Label: "Principal", Value: s.Job.Principal},
+			{Label: "Port", Value: s.CargoOperation.Port},
+			{Label: "Place of inspection", Value: s.CargoOperation.PlaceOfInspection},
+		},
+	}
+}
+
+func conditionSection(f formatter, title string, c types.ConditionResult) Section {
+	return Section{
+		Title: title,
+		Rows: []Row{
+			f.length("Mean draft FWD", c.MeanDraft.DraftFwdMean),
+			f.length("Mean draft MID", c.MeanDraft.DraftMidMean),
+			f.length("Mean draft AFT", c.MeanDraft.DraftAftMean),
+			f.length("Draft FWD corrected", c.DraftsWKeel.FwdDraftWKeel),
+			f.length("Draft MID corrected", c.DraftsWKeel.MidDraftWKeel),
+			f.length("Draft AFT corrected", c.DraftsWKeel.AftDraftWKeel),
+			f.length("MMC", c.MMC),
+			f.mass("Displacement", c.Hydrostatics.Displacement),
+			f.immersion(c.Hydrostatics.TPC),
+			f.length("LCF", c.Hydrostatics.LCF),
+			f.mass("First trim correction", c.FirstTrimCorrection),
+			f.mass("Second trim correction", c.SecondTrimCorrection),
+			f.mass("List correction", c.ListCorrection),
+			f.mass("Density correction", c.DensityCorrection),
+			f.mass("Displacement corrected to density", c.DisplCorrToDensity),
+			f.mass("Total deductibles", c.TotalDeductibles),
+			f.mass("Net displacement", c.NetDisplacement),
+		},
+	}
+}
+
+func marksSection(title string, m types.Marks) Section {
+	if len(m.Readings) > 0 {
+		section := Section{Title: title}
+		for _, r := range m.Readings {
+			section.Rows = append(section.Rows, Row{Label: r.Name, Value: r.Mark.String()})
+		}
+		return section
+	}
+	return Section{
+		Title: title,
+		Rows: []Row{
+			{Label: "FWD port", Value: m.FwdPort.String()},
+			{Label: "FWD starboard", Value: m.FwdStarboard.String()},
+			{Label: "MID port", Value: m.MidPort.String()},
+			{Label: "MID starboard", Value: m.MidStarboard.String()},
+			{Label: "AFT port", Value: m.AftPort.String()},
+			{Label: "AFT starboard", Value: m.AftStarboard.String()},
+		},
+	}
+}
+
+func warningsSection(warnings []types.Warning) Section {
+	section := Section{Title: "Warnings"}
+	for _, w := range warnings {
+		section.Rows = append(section.Rows, Row{Label: string(w.Severity), Value: w.Message})
+	}
+	return section
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getSurvey(system units.System) *types.Survey {
+	return &types.Survey{
+		Job:        types.Job{Units: system},
+		VesselData: vessel.VesselData{Name: "Test", LBP: 200, SummerDraft: 10, SummerDWT: 30000},
+	}
+}
+
+func getResult() types.SurveyResult {
+	condition := types.ConditionResult{MMC: 8, Hydrostatics: types.Hydrostatics{Displacement: 40000, TPC: 50, LCF: 2}}
+	return types.SurveyResult{
+		Initial: condition,
+		Final:   condition,
+		LoadLine: &types.LoadLineResult{
+			Allowances: types.AllowanceResult{
+				Source:           types.AllowanceSourceSummer,
+				Draft:            10,
+				Displacement:     40000,
+				TPC:              50,
+				DockDensity:      1.015,
+				FWA:              200,
+				DWA:              80,
+				PermissibleDraft: 10.08,
+			},
+			MMC: 8,
+		},
+	}
+}
+
+// rows returns the rows of the section called title, keyed by label.
+func rows(t *testing.T, l Layout, title string) map[string]string {
+	t.Helper()
+	for _, s := range l.Sections {
+		if s.Title != title {
+			continue
+		}
+		values := make(map[string]string, len(s.Rows))
+		for _, r := range s.Rows {
+			values[r.Label] = r.Value
+		}
+		return values
+	}
+	t.Fatalf("Expected section %q in %v", title, l.Sections)
+	return nil
+}
+
+func TestDraftSurvey_Units(t *testing.T) {
+	tests := []struct {
+		system    units.System
+		condition map[string]string
+		allowance map[string]string
+	}{
+		{
+			system: units.SystemMetric,
+			condition: map[string]string{
+				"MMC, m": "8.000", "Displacement, MT": "40000.000", "TPC, MT/cm": "50.000",
+			},
+			allowance: map[string]string{
+				"TPC, MT/cm": "50.000", "FWA, mm": "200.000", "DWA, mm": "80.000",
+				"Permissible draft in dock water, m": "10.080",
+			},
+		},
+		{
+			system: units.SystemImperial,
+			condition: map[string]string{
+				"MMC, ft": "26.247", "Displacement, LT": "39368.261", "TPI, LT/in": "124.994",
+			},
+			allowance: map[string]string{
+				"TPI, LT/in": "124.994", "FWA, in": "7.874", "DWA, in": "3.150",
+				"Permissible draft in dock water, ft": "33.071",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.system), func(t *testing.T) {
+			l := DraftSurvey(getSurvey(tt.system), getResult())
+			for section, expected := range map[string]map[string]string{
+				"Initial condition": tt.condition,
+				"Allowances":        tt.allowance,
+			} {
+				got := rows(t, l, section)
+				for label, value := range expected {
+					if got[label] != value {
+						t.Errorf("%s %s: expected %s, got %q", section, label, value, got[label])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAllowances_Imperial(t *testing.T) {
+	l := Allowances(getSurvey(units.SystemImperial).VesselData, getResult().LoadLine.Allowances, units.SystemImperial)
+	got := rows(t, l, "Allowances")
+	if got["FWA, in"] != "7.874" || got["TPI, LT/in"] != "124.994" {
+		t.Errorf("Expected FWA 7.874 in and TPI 124.994, got %v", got)
+	}
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func STSSurvey(mother, daughter *types.Survey, r types.TransferResult) Layout {
+	f := newFormatter(mother)
+	transfer := Section{
+		Title: "Ship-to-ship transfer",
+		Rows: []Row{
+			{Label: "Discharging vessel", Value: mother.VesselData.Name},
+			{Label: "Receiving vessel", Value: daughter.VesselData.Name},
+			f.mass("Cargo discharged by draft survey", r.MotherCargo),
+			f.mass("Cargo received by draft survey", r.DaughterCargo),
+			f.mass("Discrepancy", r.Discrepancy),
+			{Label: "Discrepancy, %", Value: num(r.DiscrepancyPercent)},
+			{Label: "Result", Value: string(r.Status)},
+		},
+	}
+
+	motherVessel := vesselSection(f, mother)
+	motherVessel.Title = "Discharging vessel"
+	daughterVessel := vesselSection(f, daughter)
+	daughterVessel.Title = "Receiving vessel"
+
+	return Layout{
+		Title: "Ship-to-Ship Transfer Draft Survey Report",
+		Sections: []Section{
+			jobSection(mother),
+			motherVessel,
+			conditionSection(f, "Discharging vessel initial condition", r.Mother.Initial),
+			conditionSection(f, "Discharging vessel final condition", r.Mother.Final),
+			daughterVessel,
+			conditionSection(f, "Receiving vessel initial condition", r.Daughter.Initial),
+			conditionSection(f, "Receiving vessel final condition", r.Daughter.Final),
+			transfer,
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+func DraftSurvey(s *types.Survey, r types.SurveyResult) Layout {
+	f := newFormatter(s)
+	cargo := Section{
+		Title: "Cargo",
+		Rows: []Row{
+			{Label: "Cargo", Value: s.CargoOperation.Cargo},
+			f.mass("Cargo by draft survey", r.Cargo),
+			f.mass("Constant", r.Constant),
+			f.mass("Constant declared", s.InitialDraft.ConstantDeclared.Tonnes()),
+			f.mass("Constant difference", r.ConstantDeclaredDiff),
+		},
+	}
+
+	sections := []Section{
+		vesselSection(f, s),
+		jobSection(s),
+		marksSection("Initial draft readings", s.InitialDraft.Marks),
+		conditionSection(f, "Initial condition", r.Initial),
+		marksSection("Final draft readings", s.FinalDraft.Marks),
+		conditionSection(f, "Final condition", r.Final),
+		cargo,
+	}
+	if len(r.Reconciliation) > 0 {
+		sections = append(sections, reconciliationSection(f, r.Reconciliation))
+	}
+	sections = append(sections, deductiblesChangeSection(f, r.DeductiblesChange))
+	if len(r.Parcels) > 0 {
+		sections = append(sections, parcelSection(f, r.Parcels))
+	}
+	if r.LoadLine != nil {
+		sections = append(sections, allowanceSection(f, r.LoadLine.Allowances), loadLineSection(f, s, *r.LoadLine))
+	}
+	if len(r.Warnings) > 0 {
+		sections = append(sections, warningsSection(r.Warnings))
+	}
+
+	return Layout{
+		Title:    "Draft Survey Report",
+		Sections: sections,
+	}
+}
+
+func reconciliationSection(f formatter, differences []types.FigureDifference) Section {
+	section := Section{Title: "Reconciliation"}
+	for _, d := range differences {
+		section.Rows = append(section.Rows,
+			f.mass(string(d.Source)+" figure", d.Declared),
+			f.mass(string(d.Source)+" difference", d.Difference),
+			Row{Label: string(d.Source) + " difference, %", Value: num(d.Percent)},
+			Row{Label: string(d.Source) + " result", Value: string(d.Status)},
+		)
+	}
+	return section
+}
+
+func loadLineSection(f formatter, s *types.Survey, l types.LoadLineResult) Section {
+	status := "OK"
+	if l.Overloaded {
+		status = "OVERLOADED"
+	}
+	return Section{
+		Title: "Load line",
+		Rows: []Row{
+			f.length("Summer draft", s.VesselData.SummerDraft.Metres()),
+			f.mass("Summer DWT", s.VesselData.SummerDWT.Tonnes()),
+			f.length("Permissible draft", l.Allowances.PermissibleDraft),
+			f.length("MMC", l.MMC),
+			f.length("Draft excess", l.DraftExcess),
+			f.mass("Current DWT", l.CurrentDWT),
+			f.mass("DWT excess", l.DWTExcess),
+			{Label: "Result", Value: status},
+		},
+	}
+}
+
+func parcelSection(f formatter, parcels []types.ParcelResult) Section {
+	section := Section{Title: "Bills of Lading"}
+	for _, p := range parcels {
+		section.Rows = append(section.Rows,
+			Row{Label: "B/L " + p.BLNumber + " receiver", Value: p.Receiver},
+			f.mass("B/L "+p.BLNumber+" declared", p.Declared),
+			f.mass("B/L "+p.BLNumber+" by draft survey", p.Quantity),
+			f.mass("B/L "+p.BLNumber+" difference", p.Difference),
+		)
+	}
+	return section
+}
+
+func deductiblesChangeSection(f formatter, c types.DeductiblesChange) Section {
+	section := Section{Title: "Ballast and deductibles change"}
+	groups := []struct {
+		title   string
+		changes []types.TankChange
+		total   float64
+	}{
+		{"Ballast", c.Ballast, c.BallastTotal},
+		{"Fresh water", c.FreshWater, c.FreshWaterTotal},
+		{"Deductibles", c.Deductibles, c.DeductiblesTotal},
+	}
+	for _, g := range groups {
+		for _, t := range g.changes {
+			row := f.mass(g.title+" "+t.Name, t.Change)
+			switch {
+			case t.OnlyInitial:
+				row.Value += " (initial only)"
+			case t.OnlyFinal:
+				row.Value += " (final only)"
+			}
+			section.Rows = append(section.Rows, row)
+		}
+		section.Rows = append(section.Rows, f.mass(g.title+" total", g.total))
+	}
+	section.Rows = append(section.Rows, f.mass("Total change", c.Total))
+	return section
+}
@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/AVZotov/draft-survey/internal/calculation"
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+const (
+	RuleTrimBeyondTable           = "trim_beyond_table"
+	RuleFTCSign                   = "ftc_sign"
+	RuleListCorrectionLarge       = "list_correction_large"
+	RuleBallastChangeExceedsCargo = "ballast_change_exceeds_cargo"
+	RuleNegativeDeductibles       = "negative_deductibles"
+	RuleDWTExceedsSummer          = "dwt_exceeds_summer"
+)
+
+type namedCondition struct {
+	name   string
+	result types.ConditionResult
+	input  types.Condition
+}
+
+func conditions(s *types.Survey, r types.SurveyResult) []namedCondition {
+	return []namedCondition{
+		{name: "initial", result: r.Initial, input: s.InitialDraft.Condition()},
+		{name: "final", result: r.Final, input: s.FinalDraft.Condition()},
+	}
+}
+
+func trueTrim(c types.ConditionResult) float64 {
+	return c.DraftsWKeel.AftDraftWKeel - c.DraftsWKeel.FwdDraftWKeel
+}
+
+func checkTrimBeyondTable(rule Rule, s *types.Survey, r types.SurveyResult) []string {
+	var messages []string
+	for _, c := range conditions(s, r) {
+		if trim := trueTrim(c.result); math.Abs(trim) > rule.Threshold {
+			messages = append(messages, fmt.Sprintf(
+				"%s condition: trim %.3f m exceeds hydrostatic table applicability of %.3f m", c.name, trim, rule.Threshold))
+		}
+	}
+	return messages
+}
+
+func checkFTCSign(_ Rule, s *types.Survey, r types.SurveyResult) []string {
+	var messages []string
+	for _, c := range conditions(s, r) {
+		expected := trueTrim(c.result) * c.result.Hydrostatics.LCF
+		ftc := c.result.FirstTrimCorrection
+		if ftc != 0 && expected != 0 && (ftc > 0) != (expected > 0) {
+			messages = append(messages, fmt.Sprintf(
+				"%s condition: first trim correction %.3f MT is inconsistent with trim %.3f m and LCF %.3f m",
+				c.name, ftc, trueTrim(c.result), c.result.Hydrostatics.LCF))
+		}
+	}
+	return messages
+}
+
+func checkListCorrectionLarge(rule Rule, s *types.Survey, r types.SurveyResult) []string {
+	var messages []string
+	for _, c := range conditions(s, r) {
+		if math.Abs(c.result.ListCorrection) > rule.Threshold {
+			messages = append(messages, fmt.Sprintf(
+				"%s condition: list correction %.3f MT exceeds %.3f MT", c.name, c.result.ListCorrection, rule.Threshold))
+		}
+	}
+	return messages
+}
+
+func checkBallastChange(rule Rule, s *types.Survey, r types.SurveyResult) []string {
+	change := calculation.TotalBallastWater(s.FinalDraft.BallastWaterTanks) -
+		calculation.TotalBallastWater(s.InitialDraft.BallastWaterTanks)
+	if r.Cargo == 0 || math.Abs(change) <= r.Cargo*rule.Threshold {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"ballast change %.3f MT exceeds %.0f%% of cargo %.3f MT", change, rule.Threshold*100, r.Cargo)}
+}
+
+func checkNegativeDeductibles(_ Rule, s *types.Survey, r types.SurveyResult) []string {
+	var messages []string
+	for _, c := range conditions(s, r) {
+		d := c.input.Deductibles
+		fields := []struct {
+			name  string
+			value units.Mass
+		}{
+			{"HFO", d.HFO}, {"MDO", d.MDO}, {"lub oil", d.LubOil}, {"bilge water", d.BilgeWater},
+			{"sewage water", d.SewageWater}, {"others", d.Others},
+		}
+		for _, f := range fields {
+			if f.value < 0 {
+				messages = append(messages, fmt.Sprintf("%s condition: %s is negative (%.3f MT)", c.name, f.name, f.value.Tonnes()))
+			}
+		}
+		for _, t := range c.input.BallastWaterTanks {
+			if t.GetWeight() < 0 {
+				messages = append(messages, fmt.Sprintf("%s condition: ballast tank %s is negative", c.name, t.Name))
+			}
+		}
+		for _, t := range c.input.FreshWaterTanks {
+			if t.GetWeight() < 0 {
+				messages = append(messages, fmt.Sprintf("%s condition: fresh water tank %s is negative", c.name, t.Name))
+			}
+		}
+		if c.result.TotalDeductibles < 0 {
+			messages = append(messages, fmt.Sprintf(
+				"%s condition: total deductibles are negative (%.3f MT)", c.name, c.result.TotalDeductibles))
+		}
+	}
+	return messages
+}
+
+func checkDWTExceedsSummer(rule Rule, s *types.Survey, r types.SurveyResult) []string {
+	if s.VesselData.SummerDWT <= 0 {
+		return nil
+	}
+	summerDWT := s.VesselData.SummerDWT.Tonnes()
+	dwt := calculation.CalcCurrentDWT(r.Final.DisplCorrToDensity, s.VesselData.Lightship)
+	if dwt <= summerDWT+rule.Threshold {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"final condition: DWT %.3f MT exceeds summer DWT %.3f MT", dwt, summerDWT)}
+}
@@ -0,0 +1,76 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+var ErrUnknownRule = errors.New("rules: unknown rule")
+
+type Rule struct {
+	ID        string         `json:"id"`
+	Enabled   bool           `json:"enabled"`
+	Severity  types.Severity `json:"severity"`
+	Threshold float64        `json:"threshold"`
+}
+
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+type check func(rule Rule, s *types.Survey, r types.SurveyResult) []string
+
+var checks = map[string]check{
+	RuleTrimBeyondTable:           checkTrimBeyondTable,
+	RuleFTCSign:                   checkFTCSign,
+	RuleListCorrectionLarge:       checkListCorrectionLarge,
+	RuleBallastChangeExceedsCargo: checkBallastChange,
+	RuleNegativeDeductibles:       checkNegativeDeductibles,
+	RuleDWTExceedsSummer:          checkDWTExceedsSummer,
+}
+
+type Engine struct {
+	rules []Rule
+}
+
+func New(cfg Config) (*Engine, error) {
+	for _, rule := range cfg.Rules {
+		if _, ok := checks[rule.ID]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownRule, rule.ID)
+		}
+	}
+	return &Engine{rules: cfg.Rules}, nil
+}
+
+func Load(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("rules: %s: %w", path, err)
+	}
+	return New(cfg)
+}
+
+func (e *Engine) Evaluate(s *types.Survey, r types.SurveyResult) []types.Warning {
+	var warnings []types.Warning
+	for _, rule := range e.rules {
+		if !rule.Enabled {
+			continue
+		}
+		severity := rule.Severity
+		if severity == "" {
+			severity = types.SeverityWarning
+		}
+		for _, msg := range checks[rule.ID](rule, s, r) {
+			warnings = append(warnings, types.Warning{Code: rule.ID, Severity: severity, Message: msg})
+		}
+	}
+	return warnings
+}
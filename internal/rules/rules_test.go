@@ -0,0 +1,101 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+func getEngine(t *testing.T) *Engine {
+	t.Helper()
+	e, err := Load("../../configs/rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func codes(warnings []types.Warning) map[string]int {
+	got := make(map[string]int)
+	for _, w := range warnings {
+		got[w.Code]++
+	}
+	return got
+}
+
+func getSurvey() (*types.Survey, types.SurveyResult) {
+	s := &types.Survey{
+		VesselData: vessel.VesselData{Lightship: 8000, SummerDWT: 50000},
+	}
+	s.InitialDraft.BallastWaterTanks = []types.BallastWaterTank{{Name: "FPT", Volume: 10000, Density: 1.025}}
+	r := types.SurveyResult{
+		Initial: types.ConditionResult{
+			DraftsWKeel:         types.DraftsWKeel{FwdDraftWKeel: 3.0, AftDraftWKeel: 5.5},
+			Hydrostatics:        types.Hydrostatics{LCF: -6.9},
+			FirstTrimCorrection: -460,
+			ListCorrection:      0.5,
+			TotalDeductibles:    11000,
+		},
+		Final: types.ConditionResult{
+			DraftsWKeel:         types.DraftsWKeel{FwdDraftWKeel: 12.0, AftDraftWKeel: 12.5},
+			Hydrostatics:        types.Hydrostatics{LCF: -2.1},
+			FirstTrimCorrection: 40,
+			ListCorrection:      7.5,
+			DisplCorrToDensity:  58500,
+			TotalDeductibles:    900,
+		},
+		Cargo: 8000,
+	}
+	return s, r
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	s, r := getSurvey()
+	got := codes(getEngine(t).Evaluate(s, r))
+
+	expected := map[string]int{
+		RuleFTCSign:                   1,
+		RuleListCorrectionLarge:       1,
+		RuleBallastChangeExceedsCargo: 1,
+		RuleDWTExceedsSummer:          1,
+	}
+	for code, n := range expected {
+		if got[code] != n {
+			t.Errorf("%s: expected %d warnings, got %d", code, n, got[code])
+		}
+	}
+	if got[RuleTrimBeyondTable] != 0 || got[RuleNegativeDeductibles] != 0 {
+		t.Errorf("Unexpected warnings: %v", got)
+	}
+}
+
+func TestEngine_Thresholds(t *testing.T) {
+	s, r := getSurvey()
+	s.FinalDraft.Deductibles.HFO = -10
+	e, err := New(Config{Rules: []Rule{
+		{ID: RuleTrimBeyondTable, Enabled: true, Threshold: 2.0},
+		{ID: RuleNegativeDeductibles, Enabled: true, Severity: types.SeverityError},
+		{ID: RuleListCorrectionLarge, Enabled: false, Threshold: 1.0},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := e.Evaluate(s, r)
+	got := codes(warnings)
+	if got[RuleTrimBeyondTable] != 1 || got[RuleNegativeDeductibles] != 1 || got[RuleListCorrectionLarge] != 0 {
+		t.Errorf("Unexpected warnings: %v", got)
+	}
+	for _, w := range warnings {
+		if w.Code == RuleTrimBeyondTable && w.Severity != types.SeverityWarning {
+			t.Errorf("Default severity: expected %s, got %s", types.SeverityWarning, w.Severity)
+		}
+	}
+}
+
+func TestNew_UnknownRule(t *testing.T) {
+	if _, err := New(Config{Rules: []Rule{{ID: "no_such_rule"}}}); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Expected %v, got %v", ErrUnknownRule, err)
+	}
+}
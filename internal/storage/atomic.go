@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// QuarantineDir is the sub-directory of a store where Recover moves files
+// that were left half-written by a crash.
+const QuarantineDir = "quarantine"
+
+const tempMarker = ".tmp-"
+
+// writeFileAtomic writes path through a temporary file in the same directory,
+// syncs it and renames it over the target, so readers only ever see the old or
+// the new content.
+func writeFileAtomic(path string, write func(w io.Writer) error) (err error) {
+	dir, base := filepath.Split(path)
+	tmp, err := os.CreateTemp(dir, "."+base+tempMarker+"*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes the directory entry after a rename. Not every platform
+// supports syncing a directory, so failures are ignored.
+func syncDir(dir string) {
+	if dir == "" {
+		dir = "."
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, tempMarker)
+}
+
+// quarantine moves path into dir/QuarantineDir under a timestamped name and
+// returns the new location.
+func quarantine(dir, path string) (string, error) {
+	qdir := filepath.Join(dir, QuarantineDir)
+	if err := os.MkdirAll(qdir, 0755); err != nil {
+		return "", err
+	}
+	name := filepath.Base(path) + "." + time.Now().UTC().Format("20060102T150405.000000000Z")
+	target := filepath.Join(qdir, name)
+	if err := os.Rename(path, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackupGenerations is used when JSONStore.BackupGenerations is not set.
+const DefaultBackupGenerations = 10
+
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+const (
+	archiveSurveys = "surveys"
+	archiveBackups = "backups"
+)
+
+var (
+	ErrBackupNotFound = errors.New("storage: no backup at the requested time")
+	ErrArchiveEntry   = errors.New("storage: invalid archive entry")
+)
+
+func (j JSONStore) generations() int {
+	if j.BackupGenerations > 0 {
+		return j.BackupGenerations
+	}
+	return DefaultBackupGenerations
+}
+
+// backup snapshots the saved survey into BackupPath/<id>/ and drops the oldest
+// generations beyond the configured limit.
+func (j JSONStore) backup(id string) error {
+	dir := filepath.Join(j.BackupPath, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	src, err := os.Open(filepath.Join(j.Path, id+".json"))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	name := time.Now().UTC().Format(backupTimeFormat) + ".json"
+	if err = writeFileAtomic(filepath.Join(dir, name), func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	backups, err := j.Backups(id)
+	if err != nil {
+		return err
+	}
+	for _, at := range backups[min(len(backups), j.generations()):] {
+		if err = os.Remove(j.backupPath(id, at)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j JSONStore) backupPath(id string, at time.Time) string {
+	return filepath.Join(j.BackupPath, id, at.UTC().Format(backupTimeFormat)+".json")
+}
+
+// Backups lists the snapshot times of a survey, newest first.
+func (j JSONStore) Backups(id string) ([]time.Time, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(filepath.Join(j.BackupPath, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []time.Time
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || isTempFile(name) {
+			continue
+		}
+		at, err := time.Parse(backupTimeFormat, strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, at)
+	}
+	sort.Slice(backups, func(a, b int) bool {
+		return backups[a].After(backups[b])
+	})
+	return backups, nil
+}
+
+// Restore replaces the saved survey with its snapshot taken at the given time,
+// as returned by Backups.
+func (j JSONStore) Restore(id string, at time.Time) error {
+	path, err := j.surveyPath(id)
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(j.backupPath(id, at))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s %s", ErrBackupNotFound, id, at.UTC().Format(time.RFC3339Nano))
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return j.withLock(id, func() error {
+		if err := writeFileAtomic(path, func(w io.Writer) error {
+			_, err := io.Copy(w, src)
+			return err
+		}); err != nil {
+			return err
+		}
+		survey, _, err := j.readSurvey(path)
+		if err != nil {
+			return err
+		}
+		return j.indexSurvey(id, survey)
+	})
+}
+
+// Archive writes every saved survey and, when BackupPath is set, every backup
+// generation to w as a gzip-compressed tar archive.
+func (j JSONStore) Archive(w io.Writer) (err error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	defer func() {
+		if cerr := tw.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		if cerr := gz.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if err = archiveDir(tw, j.Path, archiveSurveys, false); err != nil {
+		return err
+	}
+	if j.BackupPath == "" {
+		return nil
+	}
+	return archiveDir(tw, j.BackupPath, archiveBackups, true)
+}
+
+func archiveDir(tw *tar.Writer, root, prefix string, nested bool) error {
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p != root && !nested {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isTempFile(d.Name()) || d.Name() == IndexFile || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    path.Join(prefix, filepath.ToSlash(rel)),
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: info.ModTime(),
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+}
+
+// restoredIndex rebuilds the index after RestoreArchive. Without the key of an
+// encrypted archive the stale index is removed instead, to be rebuilt on the
+// first query made with the key.
+func (j JSONStore) restoredIndex() error {
+	err := j.RebuildIndex()
+	if !keyError(err) {
+		return err
+	}
+	if err = os.Remove(j.indexPath()); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// RestoreArchive extracts an archive written by Archive into the store,
+// overwriting surveys and backups with the same names, and rebuilds the index.
+func (j JSONStore) RestoreArchive(r io.Reader) (err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := gz.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return j.restoredIndex()
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		prefix, rel, _ := strings.Cut(hdr.Name, "/")
+		var root string
+		switch prefix {
+		case archiveSurveys:
+			root = j.Path
+		case archiveBackups:
+			root = j.BackupPath
+		}
+		if root == "" || !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return fmt.Errorf("%w: %q", ErrArchiveEntry, hdr.Name)
+		}
+
+		// Both surveys/<id>.json and backups/<id>/... are guarded by the
+		// lock of the survey, as in Save and Restore.
+		id, _, _ := strings.Cut(rel, "/")
+		target := filepath.Join(root, filepath.FromSlash(rel))
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err = j.withLock(strings.TrimSuffix(id, ".json"), func() error {
+			return writeFileAtomic(target, func(w io.Writer) error {
+				_, err := io.Copy(w, tr)
+				return err
+			})
+		}); err != nil {
+			return err
+		}
+	}
+}
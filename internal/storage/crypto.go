@@ -0,0 +1,330 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeyFile, kept in the data directory, holds the salt and a verifier for the
+// passphrase. It never contains the key itself.
+const KeyFile = "key.json"
+
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keySize = 32
+	keyID   = 8
+)
+
+// encryptedMagic starts every encrypted file, followed by the key ID, the
+// AES-GCM nonce and the sealed content.
+var encryptedMagic = []byte("DSENC1")
+
+var verifierPlaintext = []byte("draft-survey key verifier")
+
+var (
+	ErrWrongPassphrase = errors.New("storage: wrong passphrase")
+	ErrEncrypted       = errors.New("storage: file is encrypted and no passphrase was given")
+	ErrWrongKey        = errors.New("storage: file is encrypted with a different key")
+	ErrDecrypt         = errors.New("storage: encrypted file is corrupt or has been modified")
+	ErrKeyChanged      = errors.New("storage: passphrase has been changed; open the cipher again")
+)
+
+// Cipher encrypts store files with a passphrase-derived AES-256-GCM key. A nil
+// *Cipher leaves files in plain text. Plain-text files are always readable, so
+// encryption can be enabled on an existing store and applied with Reencrypt.
+type Cipher struct {
+	dir     string
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+type keyEntry struct {
+	ID       string `json:"id"`
+	Salt     []byte `json:"salt"`
+	Verifier []byte `json:"verifier"`
+}
+
+// keyFile lists the current key first. A second entry is the previous key of
+// a passphrase change that has not finished re-encrypting.
+type keyFile struct {
+	Keys []keyEntry `json:"keys"`
+}
+
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func newKeyEntry(passphrase string) (keyEntry, cipher.AEAD, error) {
+	id := make([]byte, keyID)
+	salt := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return keyEntry{}, nil, err
+	}
+	if _, err := rand.Read(salt); err != nil {
+		return keyEntry{}, nil, err
+	}
+	aead, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return keyEntry{}, nil, err
+	}
+	entry := keyEntry{ID: hex.EncodeToString(id), Salt: salt}
+	if entry.Verifier, err = sealWith(aead, entry.ID, verifierPlaintext); err != nil {
+		return keyEntry{}, nil, err
+	}
+	return entry, aead, nil
+}
+
+// unlock derives the key of entry and checks it against the verifier.
+func (e keyEntry) unlock(passphrase string) (cipher.AEAD, error) {
+	aead, err := deriveKey(passphrase, e.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = openWith(aead, e.Verifier); err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return aead, nil
+}
+
+func readKeyFile(dir string) (keyFile, error) {
+	var kf keyFile
+	data, err := os.ReadFile(filepath.Join(dir, KeyFile))
+	if err != nil {
+		return kf, err
+	}
+	if err = json.Unmarshal(data, &kf); err != nil {
+		return kf, err
+	}
+	if len(kf.Keys) == 0 {
+		return kf, fmt.Errorf("storage: %s lists no keys", KeyFile)
+	}
+	return kf, nil
+}
+
+func writeKeyFile(dir string, kf keyFile) error {
+	return writeFileAtomic(filepath.Join(dir, KeyFile), func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(kf)
+	})
+}
+
+// OpenCipher unlocks the key described by dir/key.json, creating the file for
+// a new passphrase when it does not exist yet.
+func OpenCipher(dir, passphrase string) (*Cipher, error) {
+	kf, err := readKeyFile(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		entry, aead, err := newKeyEntry(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		if err = writeKeyFile(dir, keyFile{Keys: []keyEntry{entry}}); err != nil {
+			return nil, err
+		}
+		return &Cipher{dir: dir, current: entry.ID, keys: map[string]cipher.AEAD{entry.ID: aead}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := kf.Keys[0]
+	aead, err := entry.unlock(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{dir: dir, current: entry.ID, keys: map[string]cipher.AEAD{entry.ID: aead}}, nil
+}
+
+// lock takes the key lock in the directory of key.json. Writers hold it
+// shared; re-encryption holds it exclusively, so no file is written with a
+// key that is being retired. A nil *Cipher takes no lock.
+func (c *Cipher) lock(exclusive bool, timeout time.Duration) (func() error, error) {
+	if c == nil || c.dir == "" {
+		return func() error { return nil }, nil
+	}
+	return lockFile(c.dir, keyLock, exclusive, timeout)
+}
+
+// hold takes the key lock shared and checks that the key of c is still the
+// current one, so a process opened before a passphrase change does not write
+// files under the retired key.
+func (c *Cipher) hold(timeout time.Duration) (func() error, error) {
+	unlock, err := c.lock(false, timeout)
+	if err != nil || c == nil || c.dir == "" {
+		return unlock, err
+	}
+	kf, err := readKeyFile(c.dir)
+	if err == nil && kf.Keys[0].ID != c.current {
+		err = ErrKeyChanged
+	}
+	if err != nil {
+		_ = unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// ChangePassphrase re-encrypts every survey, draft, backup and the user
+// profile under a key derived from newPassphrase. The previous key stays in
+// key.json until all files are rewritten, so an interrupted change is
+// finished by calling ChangePassphrase again with the same passphrases.
+// Writers are held off by the key lock for the whole change; ciphers opened
+// with the old passphrase fail with ErrKeyChanged afterwards.
+func ChangePassphrase(dir, oldPassphrase, newPassphrase string, surveys JSONStore, user UserStore) error {
+	unlock, err := lockFile(dir, keyLock, true, surveys.lockTimeout())
+	if err != nil {
+		return err
+	}
+	return runLocked(unlock, func() error {
+		return changePassphrase(dir, oldPassphrase, newPassphrase, surveys, user)
+	})
+}
+
+func changePassphrase(dir, oldPassphrase, newPassphrase string, surveys JSONStore, user UserStore) error {
+	kf, err := readKeyFile(dir)
+	if err != nil {
+		return err
+	}
+
+	var next, previous keyEntry
+	var nextAEAD, previousAEAD cipher.AEAD
+	if len(kf.Keys) > 1 {
+		next, previous = kf.Keys[0], kf.Keys[1]
+		if nextAEAD, err = next.unlock(newPassphrase); err != nil {
+			return err
+		}
+		if previousAEAD, err = previous.unlock(oldPassphrase); err != nil {
+			return err
+		}
+	} else {
+		previous = kf.Keys[0]
+		if previousAEAD, err = previous.unlock(oldPassphrase); err != nil {
+			return err
+		}
+		if next, nextAEAD, err = newKeyEntry(newPassphrase); err != nil {
+			return err
+		}
+		if err = writeKeyFile(dir, keyFile{Keys: []keyEntry{next, previous}}); err != nil {
+			return err
+		}
+	}
+
+	c := &Cipher{
+		dir:     dir,
+		current: next.ID,
+		keys:    map[string]cipher.AEAD{next.ID: nextAEAD, previous.ID: previousAEAD},
+	}
+	surveys.Cipher, user.Cipher = c, c
+	if err = surveys.reencrypt(); err != nil {
+		return err
+	}
+	if err = user.reencrypt(); err != nil {
+		return err
+	}
+	return writeKeyFile(dir, keyFile{Keys: []keyEntry{next}})
+}
+
+func sealWith(aead cipher.AEAD, id string, plain []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header := append(append([]byte{}, encryptedMagic...), []byte(id)...)
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plain, header), nil
+}
+
+func openWith(aead cipher.AEAD, data []byte) ([]byte, error) {
+	headerSize := len(encryptedMagic) + 2*keyID
+	if len(data) < headerSize+aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	header := data[:headerSize]
+	nonce := data[headerSize : headerSize+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, data[headerSize+aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// seal encrypts plain with the current key; a nil Cipher returns it as is.
+func (c *Cipher) seal(plain []byte) ([]byte, error) {
+	if c == nil {
+		return plain, nil
+	}
+	return sealWith(c.keys[c.current], c.current, plain)
+}
+
+// open decrypts data written by seal. Plain-text data is returned unchanged.
+func (c *Cipher) open(data []byte) ([]byte, error) {
+	if !isEncrypted(data) {
+		return data, nil
+	}
+	if c == nil {
+		return nil, ErrEncrypted
+	}
+	idEnd := len(encryptedMagic) + 2*keyID
+	if len(data) < idEnd {
+		return nil, ErrDecrypt
+	}
+	aead, ok := c.keys[string(data[len(encryptedMagic):idEnd])]
+	if !ok {
+		return nil, ErrWrongKey
+	}
+	return openWith(aead, data)
+}
+
+// keyError reports whether err means the file could not be opened with the
+// keys at hand, as opposed to being damaged.
+func keyError(err error) bool {
+	return errors.Is(err, ErrEncrypted) || errors.Is(err, ErrWrongKey)
+}
+
+// reencryptFile rewrites one file under the current key, skipping files that
+// already use it.
+func (c *Cipher) reencryptFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if c != nil && bytes.HasPrefix(data, append(append([]byte{}, encryptedMagic...), c.current...)) {
+		return nil
+	}
+	plain, err := c.open(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	sealed, err := c.seal(plain)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(sealed)
+		return err
+	})
+}
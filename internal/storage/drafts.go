@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+var ErrTempPath = errors.New("storage: TempPath must be set and differ from Path")
+
+// DefaultAutoSaveInterval is used when AutoSaver.Interval is not set.
+const DefaultAutoSaveInterval = 30 * time.Second
+
+// Draft is an auto-saved, not yet promoted survey found in TempPath.
+type Draft struct {
+	ID      string
+	SavedAt time.Time
+}
+
+func (j JSONStore) checkTempPath() error {
+	if j.TempPath == "" || filepath.Clean(j.TempPath) == filepath.Clean(j.Path) {
+		return ErrTempPath
+	}
+	return nil
+}
+
+// draftLock names the lock guarding the draft of a survey. It is taken before
+// the lock of the survey itself.
+func draftLock(id string) string {
+	return id + ".draft"
+}
+
+func (j JSONStore) draftPath(id string) (string, error) {
+	if err := j.checkTempPath(); err != nil {
+		return "", err
+	}
+	if err := ValidateID(id); err != nil {
+		return "", err
+	}
+	return filepath.Join(j.TempPath, id+".json"), nil
+}
+
+// SaveDraft writes an in-progress survey to TempPath. Saved surveys in Path
+// are left untouched.
+func (j JSONStore) SaveDraft(id string, survey *types.Survey) error {
+	path, err := j.draftPath(id)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(j.TempPath, 0755); err != nil {
+		return err
+	}
+	return j.withLock(draftLock(id), func() error {
+		return j.writeSurvey(path, survey)
+	})
+}
+
+func (j JSONStore) GetDraft(id string) (*types.Survey, error) {
+	path, err := j.draftPath(id)
+	if err != nil {
+		return nil, err
+	}
+	survey, _, err := j.readSurvey(path)
+	return survey, err
+}
+
+// Drafts lists recoverable drafts, most recently saved first. A missing
+// TempPath means there is nothing to recover.
+func (j JSONStore) Drafts() ([]Draft, error) {
+	if err := j.checkTempPath(); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(j.TempPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []Draft
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || isTempFile(name) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, Draft{ID: strings.TrimSuffix(name, ".json"), SavedAt: info.ModTime()})
+	}
+	sort.SliceStable(drafts, func(a, b int) bool {
+		return drafts[a].SavedAt.After(drafts[b].SavedAt)
+	})
+	return drafts, nil
+}
+
+// PromoteDraft saves the draft as a regular survey and removes it from
+// TempPath. A draft saved meanwhile waits, so it is not removed unsaved.
+func (j JSONStore) PromoteDraft(id string) error {
+	path, err := j.draftPath(id)
+	if err != nil {
+		return err
+	}
+	return j.withLock(draftLock(id), func() error {
+		survey, _, err := j.readSurvey(path)
+		if err != nil {
+			return err
+		}
+		if err = j.Save(id, survey); err != nil {
+			return err
+		}
+		return removeDraft(path)
+	})
+}
+
+// DeleteDraft removes the draft of a survey. It is called once the final
+// report has been generated; a missing draft is not an error.
+func (j JSONStore) DeleteDraft(id string) error {
+	path, err := j.draftPath(id)
+	if err != nil {
+		return err
+	}
+	return j.withFileLock(draftLock(id), func() error {
+		return removeDraft(path)
+	})
+}
+
+func removeDraft(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// AutoSaver periodically writes the survey returned by Current as a draft.
+type AutoSaver struct {
+	Store JSONStore
+	ID    string
+	// Interval between saves; DefaultAutoSaveInterval when zero or negative.
+	Interval time.Duration
+	// Current returns a snapshot of the survey being edited, or nil when there
+	// is nothing to save yet. It is called from the Run goroutine.
+	Current func() *types.Survey
+	// OnError, when set, receives save failures; auto-save keeps running.
+	OnError func(error)
+}
+
+// Run saves a draft every Interval until ctx is cancelled, then saves once
+// more so the latest edits are not lost. It returns at once when Current is
+// not set.
+func (a AutoSaver) Run(ctx context.Context) {
+	if a.Current == nil {
+		return
+	}
+	ticker := time.NewTicker(a.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.save()
+			return
+		case <-ticker.C:
+			a.save()
+		}
+	}
+}
+
+func (a AutoSaver) interval() time.Duration {
+	if a.Interval > 0 {
+		return a.Interval
+	}
+	return DefaultAutoSaveInterval
+}
+
+func (a AutoSaver) save() {
+	survey := a.Current()
+	if survey == nil {
+		return
+	}
+	if err := a.Store.SaveDraft(a.ID, survey); err != nil && a.OnError != nil {
+		a.OnError(err)
+	}
+}
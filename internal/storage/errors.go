@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileError is a survey that could not be read during GetAll. File is the
+// file name for JSONStore and the survey ID for SQLiteStore.
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// CorruptFilesError is returned by GetAll together with the surveys that were
+// read successfully, so one bad file does not hide the rest.
+type CorruptFilesError struct {
+	Files []FileError
+}
+
+func (e *CorruptFilesError) Error() string {
+	problems := make([]string, len(e.Files))
+	for i, f := range e.Files {
+		problems[i] = f.Error()
+	}
+	return fmt.Sprintf("storage: %d unreadable surveys: %s", len(e.Files), strings.Join(problems, "; "))
+}
+
+func (e *CorruptFilesError) Unwrap() []error {
+	errs := make([]error, len(e.Files))
+	for i, f := range e.Files {
+		errs[i] = f
+	}
+	return errs
+}
+
+func (e *CorruptFilesError) add(file string, err error) {
+	e.Files = append(e.Files, FileError{File: file, Err: err})
+}
+
+// result returns nil when no problems were recorded, so the error can be
+// returned unconditionally.
+func (e *CorruptFilesError) result() error {
+	if len(e.Files) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidID = errors.New("storage: survey ID must be a UUID")
+
+// NewID returns a random survey ID in the canonical UUID form used for file
+// names (UUID.json).
+func NewID() string {
+	return uuid.NewString()
+}
+
+// ValidateID accepts only canonical lower-case UUIDs, so an ID can never
+// name a path outside the store.
+func ValidateID(id string) error {
+	parsed, err := uuid.Parse(id)
+	if err != nil || parsed.String() != id {
+		return fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return nil
+}
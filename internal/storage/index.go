@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+// IndexFile holds the summaries of every survey in JSONStore.Path so the
+// survey list does not have to decode each survey file. It is derived data
+// and is rebuilt whenever it is missing.
+const IndexFile = "_index.json"
+
+type index map[string]SurveySummary
+
+func (j JSONStore) indexPath() string {
+	return filepath.Join(j.Path, IndexFile)
+}
+
+// readIndex reads the index file. ok is false when the file is missing or
+// does not decode and the index has to be rebuilt.
+func (j JSONStore) readIndex() (idx index, ok bool, err error) {
+	data, err := os.ReadFile(j.indexPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if data, err = j.Cipher.open(data); err != nil {
+		return nil, false, err
+	}
+	idx = index{}
+	if err = json.Unmarshal(data, &idx); err != nil {
+		return nil, false, nil
+	}
+	return idx, true, nil
+}
+
+// loadIndex reads the index, rebuilding it in memory when needed. It is
+// called with the index lock held by callers that write the index back.
+func (j JSONStore) loadIndex() (index, error) {
+	idx, ok, err := j.readIndex()
+	if err != nil || ok {
+		return idx, err
+	}
+	return j.buildIndex()
+}
+
+func (j JSONStore) writeIndex(idx index) error {
+	plain, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	data, err := j.Cipher.seal(plain)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(j.indexPath(), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+func (j JSONStore) buildIndex() (index, error) {
+	files, err := os.ReadDir(j.Path)
+	if err != nil {
+		return nil, err
+	}
+	idx := index{}
+	for _, file := range files {
+		if !isSurveyFile(file) {
+			continue
+		}
+		id := strings.TrimSuffix(file.Name(), ".json")
+		survey, _, err := j.readSurvey(filepath.Join(j.Path, file.Name()))
+		if keyError(err) {
+			return nil, err
+		}
+		if err != nil || ValidateID(id) != nil {
+			// Unreadable surveys are reported by GetAll and Recover.
+			continue
+		}
+		idx[id] = summarize(id, survey)
+	}
+	return idx, nil
+}
+
+// RebuildIndex rescans every survey file and rewrites the index.
+func (j JSONStore) RebuildIndex() error {
+	return j.withLock(indexLock, func() error {
+		idx, err := j.buildIndex()
+		if err != nil {
+			return err
+		}
+		return j.writeIndex(idx)
+	})
+}
+
+// indexSurvey and unindexSurvey update one entry under the index lock. They
+// are called with the survey's own lock held, always in that order.
+func (j JSONStore) indexSurvey(id string, survey *types.Survey) error {
+	return j.withLock(indexLock, func() error {
+		idx, err := j.loadIndex()
+		if err != nil {
+			return err
+		}
+		idx[id] = summarize(id, survey)
+		return j.writeIndex(idx)
+	})
+}
+
+func (j JSONStore) unindexSurvey(id string) error {
+	return j.withLock(indexLock, func() error {
+		idx, err := j.loadIndex()
+		if err != nil {
+			return err
+		}
+		delete(idx, id)
+		return j.writeIndex(idx)
+	})
+}
+
+// Query reads the index, rebuilding and saving it first when it is missing.
+func (j JSONStore) Query(q SurveyQuery) (SurveyPage, error) {
+	idx, ok, err := j.readIndex()
+	if err == nil && !ok {
+		err = j.withLock(indexLock, func() error {
+			if idx, ok, err = j.readIndex(); err != nil || ok {
+				return err
+			}
+			if idx, err = j.buildIndex(); err != nil {
+				return err
+			}
+			return j.writeIndex(idx)
+		})
+	}
+	if err != nil {
+		return SurveyPage{}, err
+	}
+	summaries := make([]SurveySummary, 0, len(idx))
+	for _, s := range idx {
+		summaries = append(summaries, s)
+	}
+	return q.apply(summaries), nil
+}
+
+// isSurveyFile reports whether a directory entry of JSONStore.Path is a saved
+// survey rather than the index, a temporary file or a sub-directory.
+func isSurveyFile(file os.DirEntry) bool {
+	name := file.Name()
+	return !file.IsDir() && !isTempFile(name) && name != IndexFile && name != KeyFile &&
+		strings.HasSuffix(name, ".json")
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultLockTimeout is used when JSONStore.LockTimeout is not set.
+const DefaultLockTimeout = 5 * time.Second
+
+// LockDir is the sub-directory of JSONStore.Path, and of the directory holding
+// KeyFile, with the advisory lock files. Lock files are left in place; only the lock on them matters.
+const LockDir = ".locks"
+
+const (
+	indexLock        = "_index"
+	keyLock          = "_key"
+	lockPollInterval = 10 * time.Millisecond
+)
+
+var ErrLockTimeout = errors.New("storage: timed out waiting for lock")
+
+func (j JSONStore) lockTimeout() time.Duration {
+	if j.LockTimeout > 0 {
+		return j.LockTimeout
+	}
+	return DefaultLockTimeout
+}
+
+// lock takes the exclusive advisory lock called name, shared by every process
+// and goroutine using the same Path, and returns the function releasing it.
+// With a Cipher the key lock is held shared first, see Cipher.hold.
+func (j JSONStore) lock(name string) (func() error, error) {
+	release, err := j.Cipher.hold(j.lockTimeout())
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockFile(j.Path, name, true, j.lockTimeout())
+	if err != nil {
+		_ = release()
+		return nil, err
+	}
+	return func() error {
+		err := unlock()
+		if rerr := release(); rerr != nil && err == nil {
+			err = rerr
+		}
+		return err
+	}, nil
+}
+
+// withLock runs fn while holding the lock called name.
+func (j JSONStore) withLock(name string, fn func() error) error {
+	unlock, err := j.lock(name)
+	if err != nil {
+		return err
+	}
+	return runLocked(unlock, fn)
+}
+
+// withFileLock is withLock without the key lock, for callers that already
+// hold it exclusively.
+func (j JSONStore) withFileLock(name string, fn func() error) error {
+	unlock, err := lockFile(j.Path, name, true, j.lockTimeout())
+	if err != nil {
+		return err
+	}
+	return runLocked(unlock, fn)
+}
+
+// runLocked runs fn and then unlock, returning the first error.
+func runLocked(unlock func() error, fn func() error) (err error) {
+	defer func() {
+		if uerr := unlock(); uerr != nil && err == nil {
+			err = uerr
+		}
+	}()
+	return fn()
+}
+
+// lockFile takes the advisory lock on dir/LockDir/name.lock, shared or
+// exclusive, waiting up to timeout for it. A zero timeout tries once.
+func lockFile(dir, name string, exclusive bool, timeout time.Duration) (func() error, error) {
+	dir = filepath.Join(dir, LockDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name+".lock")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(file, exclusive)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			_ = file.Close()
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, name)
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	return func() error {
+		err := unlockFile(file)
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		return err
+	}, nil
+}
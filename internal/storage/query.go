@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+type SortField string
+
+const (
+	SortByDate      SortField = "date"
+	SortByJobNumber SortField = "job_number"
+)
+
+// SurveyQuery selects surveys for the survey list. Empty fields do not
+// filter; text fields match case-insensitively anywhere in the value. Date
+// is the start of the initial draft and From/To are inclusive.
+type SurveyQuery struct {
+	Vessel     string // name or IMO
+	Port       string
+	Principal  string
+	Cargo      string
+	From       time.Time
+	To         time.Time
+	Status     types.SurveyStatus
+	Sort       SortField
+	Descending bool
+	Offset     int
+	Limit      int // 0 returns every match
+}
+
+// SurveySummary is the list-page view of a survey.
+type SurveySummary struct {
+	ID         string
+	Kind       types.SurveyKind
+	Status     types.SurveyStatus
+	VesselName string
+	IMO        string
+	Port       string
+	Principal  string
+	Cargo      string
+	JobNumber  int
+	Date       time.Time
+}
+
+// SurveyPage is one page of query results; Total counts every match.
+type SurveyPage struct {
+	Summaries []SurveySummary
+	Total     int
+}
+
+func summarize(id string, s *types.Survey) SurveySummary {
+	return SurveySummary{
+		ID:         id,
+		Kind:       s.Kind,
+		Status:     s.Status,
+		VesselName: s.VesselData.Name,
+		IMO:        s.VesselData.IMO,
+		Port:       s.CargoOperation.Port,
+		Principal:  s.Job.Principal,
+		Cargo:      s.CargoOperation.Cargo,
+		JobNumber:  s.Job.JobNumber,
+		Date:       s.InitialDraft.StartedAt.UTC(),
+	}
+}
+
+func contains(value, part string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(part))
+}
+
+func (q SurveyQuery) matches(s SurveySummary) bool {
+	switch {
+	case q.Vessel != "" && !contains(s.VesselName, q.Vessel) && !contains(s.IMO, q.Vessel):
+	case q.Port != "" && !contains(s.Port, q.Port):
+	case q.Principal != "" && !contains(s.Principal, q.Principal):
+	case q.Cargo != "" && !contains(s.Cargo, q.Cargo):
+	case !q.From.IsZero() && s.Date.Before(q.From):
+	case !q.To.IsZero() && s.Date.After(q.To):
+	case q.Status != "" && s.Status != q.Status:
+	default:
+		return true
+	}
+	return false
+}
+
+// apply filters, sorts and paginates summaries in memory.
+func (q SurveyQuery) apply(summaries []SurveySummary) SurveyPage {
+	var matched []SurveySummary
+	for _, s := range summaries {
+		if q.matches(s) {
+			matched = append(matched, s)
+		}
+	}
+
+	less := func(a, b SurveySummary) bool {
+		if q.Sort == SortByJobNumber && a.JobNumber != b.JobNumber {
+			return a.JobNumber < b.JobNumber
+		}
+		if q.Sort != SortByJobNumber && !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.ID < b.ID
+	}
+	sort.Slice(matched, func(a, b int) bool {
+		if q.Descending {
+			return less(matched[b], matched[a])
+		}
+		return less(matched[a], matched[b])
+	})
+
+	page := SurveyPage{Total: len(matched)}
+	start := min(max(q.Offset, 0), len(matched))
+	end := len(matched)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, end)
+	}
+	page.Summaries = matched[start:end]
+	return page
+}
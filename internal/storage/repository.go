@@ -5,10 +5,12 @@ import (
 )
 
 type SurveyRepository interface {
+	Create(survey *types.Survey) (string, error)
 	Save(id string, survey *types.Survey) error
 	Get(id string) (*types.Survey, error)
 	GetAll() ([]*types.Survey, error)
 	Delete(id string) error
+	Query(q SurveyQuery) (SurveyPage, error)
 }
 
 type UserRepository interface {
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+// SchemaVersion is the version of the survey file format written by this
+// build. Version 0 is the untagged format that used Go field names as keys;
+// version 1 introduced snake_case keys and the schema_version field.
+const SchemaVersion = 1
+
+var ErrSchemaVersion = errors.New("storage: survey file schema is newer than supported")
+
+// migrations[n] upgrades a decoded survey document from version n to n+1.
+var migrations = []func(doc map[string]any) error{
+	migrateV0,
+}
+
+// surveyFile is the on-disk layout: the survey fields with schema_version
+// alongside them.
+type surveyFile struct {
+	SchemaVersion int `json:"schema_version"`
+	*types.Survey
+}
+
+func encodeSurvey(w io.Writer, survey *types.Survey) error {
+	return json.NewEncoder(w).Encode(surveyFile{SchemaVersion: SchemaVersion, Survey: survey})
+}
+
+// decodeSurvey reads a survey of any supported schema version, upgrading it
+// in memory, and returns the version it was stored with.
+func decodeSurvey(data []byte) (*types.Survey, int, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, 0, err
+	}
+	version := header.SchemaVersion
+	if version > SchemaVersion || version < 0 {
+		return nil, version, fmt.Errorf("%w: %d", ErrSchemaVersion, version)
+	}
+
+	if version < SchemaVersion {
+		var err error
+		if data, err = migrate(data, version); err != nil {
+			return nil, version, err
+		}
+	}
+
+	file := surveyFile{Survey: &types.Survey{}}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, version, err
+	}
+	return file.Survey, version, nil
+}
+
+func migrate(data []byte, from int) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	doc := map[string]any{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for version := from; version < SchemaVersion; version++ {
+		if err := migrations[version](doc); err != nil {
+			return nil, fmt.Errorf("storage: migrating survey from schema %d: %w", version, err)
+		}
+	}
+	doc["schema_version"] = SchemaVersion
+	return json.Marshal(doc)
+}
+
+// migrateV0 renames Go field names to the snake_case keys of version 1.
+// Survey documents contain no maps, so every object key is a field name.
+func migrateV0(doc map[string]any) error {
+	renameKeys(doc)
+	return nil
+}
+
+func renameKeys(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			value := v[key]
+			renameKeys(value)
+			if snake := snakeCase(key); snake != key {
+				delete(v, key)
+				v[snake] = value
+			}
+		}
+	case []any:
+		for _, value := range v {
+			renameKeys(value)
+		}
+	}
+}
+
+// snakeCase converts a Go field name to its version 1 key, keeping acronyms
+// together: TPCListPort becomes tpc_list_port and IMO becomes imo.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
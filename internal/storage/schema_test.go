@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+	"github.com/AVZotov/draft-survey/internal/units"
+	"github.com/AVZotov/draft-survey/internal/vessel"
+)
+
+const goldenID = "5b0c7c1e-2f1a-4d8e-9c3b-7a6d5e4f3a21"
+
+// getGoldenSurvey is the survey stored in every testdata/survey_v*.json
+// fixture. It fills at least one field of every persisted type.
+func getGoldenSurvey() *types.Survey {
+	started := time.Date(2026, 3, 4, 8, 30, 0, 0, time.UTC)
+	marks := types.Marks{
+		FwdPort:      types.Mark{Value: 5.12, Method: types.ReadingMethodDirect, Unit: types.DraftUnitMetre},
+		FwdStarboard: types.Mark{Value: 5.14, Method: types.ReadingMethodDirect, Unit: types.DraftUnitMetre},
+		MidPort:      types.Mark{Unit: types.DraftUnitFeetInches, Feet: 18, Inches: 6.5},
+		MidStarboard: types.Mark{Value: 5.66, Method: types.ReadingMethodWaterline, Unit: types.DraftUnitMetre},
+		AftPort:      types.Mark{Value: 6.2, Method: types.ReadingMethodDirect, Unit: types.DraftUnitMetre},
+		AftStarboard: types.Mark{Value: 6.22, Method: types.ReadingMethodDirect, Unit: types.DraftUnitMetre},
+		Readings: []types.MarkReading{
+			{Name: "F1P", Mark: types.Mark{Value: 5.12, Unit: types.DraftUnitMetre}},
+		},
+	}
+	deductibles := types.Deductibles{
+		HFO:              350.5,
+		MDO:              42.1,
+		LubOil:           8.3,
+		BilgeWater:       2.2,
+		SewageWater:      4.4,
+		OtherDeductibles: types.OtherDeductibles{Others: 1.5, OthersName: "Paint"},
+		IceAccretion:     []types.IceAccretion{{Zone: "Forecastle", Area: 120, Thickness: 0.05, Density: 0.9}},
+	}
+	hydrostatics := []types.HydrostaticRow{
+		{Draft: 5.6, Displacement: 9800, TPC: 22.4, LCF: 1.25, LCFDirection: types.LCFDirectionAft},
+		{Draft: 5.7, Displacement: 10024, TPC: 22.5, LCF: 1.2, LCFDirection: types.LCFDirectionAft},
+	}
+	mtc := []types.MTCRow{{Draft: 6.1, MTC: 180.2}, {Draft: 5.1, MTC: 172.4}}
+
+	return &types.Survey{
+		Surveyor: &types.User{
+			LastName:   "Dow",
+			FirstName:  "John",
+			Company:    "NoName",
+			Position:   "Surveyor",
+			EmployeeID: "12345",
+		},
+		ID:     goldenID,
+		Kind:   types.SurveyKindSTS,
+		Status: types.SurveyStatusReported,
+		InitialDraft: types.InitialDraft{
+			BallastWaterTanks: []types.BallastWaterTank{{Name: "WBT 1 P", Sounding: 3.2, Volume: 512.4, Density: 1.022}},
+			FreshWaterTanks:   []types.FreshWaterTank{{Name: "FWT P", Sounding: 2.1, Volume: 80.5}},
+			Deductibles:       deductibles,
+			Marks:             marks,
+			ConstantDeclared:  600,
+			Density:           1.021,
+			StartedAt:         started,
+			FinishedAt:        started.Add(90 * time.Minute),
+			MTCRows:           mtc,
+			HydrostaticRows:   hydrostatics,
+			HydrostaticUnit:   types.HydrostaticUnitMetric,
+			TPCListPort:       22.4,
+			TPCListStarboard:  22.5,
+			SeaCondition:      types.SeaCondition{Type: types.SeaConditionTypeWave, Wave: types.WaveConditionSmooth},
+		},
+		FinalDraft: types.FinalDraft{
+			Deductibles:     deductibles,
+			Marks:           marks,
+			CargoDeclared:   25000,
+			Density:         1.02,
+			StartedAt:       started.Add(48 * time.Hour),
+			MTCRows:         mtc,
+			HydrostaticRows: hydrostatics,
+			HydrostaticUnit: types.HydrostaticUnitMetric,
+			SeaCondition:    types.SeaCondition{Type: types.SeaConditionTypeIce, Ice: types.IceCondition010To015},
+		},
+		Job: types.Job{
+			JobNumber: 123456,
+			DSNumber:  7,
+			Principal: "Cargill",
+			Units:     units.SystemMetric,
+		},
+		CargoOperation: types.CargoOperation{
+			PlaceOfInspection: "Anchorage",
+			Destination:       "Alexandria",
+			Operation:         "Loading",
+			Origin:            "Russia",
+			Cargo:             "Wheat",
+			Packing:           "Bulk",
+			Port:              "Novorossiysk",
+			Figures:           types.CargoFigures{BillOfLading: 25010, ShoreScale: 24990},
+			Parcels:           []types.Parcel{{BLNumber: "BL-1", Receiver: "Receiver A", Declared: 25010, Intermediate: 24995}},
+			ApportionMethod:   types.ApportionProRata,
+		},
+		VesselData: vessel.VesselData{
+			Name:                 "Ocean Star",
+			Flag:                 "Malta",
+			HomePort:             "Valletta",
+			IMO:                  "9111111",
+			BuiltCountry:         "Japan",
+			BuiltYear:            2010,
+			HydrostaticDocsPhoto: "docs/9111111.jpg",
+			Lightship:            8390,
+			Breadth:              32.26,
+			Depth:                18.5,
+			LBP:                  182,
+			SummerDraft:          12.8,
+			SummerDWT:            56000,
+			SummerTPC:            57.2,
+			SummerFreeboard:      5.7,
+			DistancePPFwd:        2.3,
+			PPFwdDirection:       vessel.PPDirectionAft,
+			DistancePPMid:        0.5,
+			PPMidDirection:       vessel.PPDirectionForward,
+			DistancePPAft:        5.1,
+			PPAftDirection:       vessel.PPDirectionForward,
+			KeelFwd:              0.018,
+			KeelMid:              0.018,
+			KeelAft:              0.018,
+			VesselType:           vessel.VesselTypeMarine,
+			CorrectionMethod:     vessel.CorrectionMethodFullLBP,
+			MarkLayout: []vessel.DraftMark{
+				{Name: "F1P", Station: vessel.MarkStationFwd, Side: vessel.MarkSidePort, Distance: 2.3, Direction: vessel.PPDirectionAft},
+			},
+			SingleSideRule: vessel.SingleSideRuleReject,
+		},
+		Convoy: []types.ConvoyUnit{{
+			VesselData:   vessel.VesselData{Name: "Barge 1", VesselType: vessel.VesselTypeBarge},
+			InitialDraft: types.InitialDraft{Marks: marks, Density: 1.0},
+			FinalDraft:   types.FinalDraft{Marks: marks, Density: 1.0},
+		}},
+		STS: &types.STSLink{Role: types.STSRoleMother, CounterpartID: "0b9f0e4a-1c2d-4e3f-8a5b-6c7d8e9f0a1b"},
+	}
+}
+
+func readFixture(t *testing.T, version int) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("survey_v%d.json", version)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecodeSurvey_Fixtures(t *testing.T) {
+	expected := getGoldenSurvey()
+	for version := 0; version <= SchemaVersion; version++ {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			got, gotVersion, err := decodeSurvey(readFixture(t, version))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotVersion != version {
+				t.Errorf("Version: expected %d, got %d", version, gotVersion)
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Errorf("Expected %+v, got %+v", expected, got)
+			}
+		})
+	}
+}
+
+func TestEncodeSurvey_CurrentFixture(t *testing.T) {
+	var expected bytes.Buffer
+	if err := json.Compact(&expected, readFixture(t, SchemaVersion)); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := encodeSurvey(&got, getGoldenSurvey()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected.Bytes(), bytes.TrimSpace(got.Bytes())) {
+		t.Errorf("Encoded survey differs from testdata/survey_v%d.json:\n%s", SchemaVersion, got.String())
+	}
+}
+
+func TestDecodeSurvey_NewerVersion(t *testing.T) {
+	_, _, err := decodeSurvey([]byte(`{"schema_version": 99}`))
+	if !errors.Is(err, ErrSchemaVersion) {
+		t.Errorf("Expected %v, got %v", ErrSchemaVersion, err)
+	}
+}
+
+func TestJSONStore_RewriteOnRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, goldenID+".json")
+	if err := os.WriteFile(path, readFixture(t, 0), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := JSONStore{Path: dir, TempPath: dir, RewriteOnRead: true}
+	if _, err := store.Get(goldenID); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, version, err := decodeSurvey(data); err != nil || version != SchemaVersion {
+		t.Errorf("Expected file rewritten in version %d, got %d (%v)", SchemaVersion, version, err)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ID":              "id",
+		"IMO":             "imo",
+		"TPCListPort":     "tpc_list_port",
+		"DistancePPFwd":   "distance_pp_fwd",
+		"MTCRows":         "mtc_rows",
+		"CounterpartID":   "counterpart_id",
+		"last_name":       "last_name",
+		"HydrostaticUnit": "hydrostatic_unit",
+		"BLNumber":        "bl_number",
+		"LCFDirection":    "lcf_direction",
+	} {
+		if got := snakeCase(name); got != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, got)
+		}
+	}
+}
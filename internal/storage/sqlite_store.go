@@ -0,0 +1,450 @@
+package storage
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+
+	"github.com/AVZotov/draft-survey/internal/types"
+)
+
+var (
+	_ SurveyRepository = (*SQLiteStore)(nil)
+	_ UserRepository   = (*SQLiteUserStore)(nil)
+)
+
+func init() {
+	// unicode_lower folds case the way SurveyQuery.matches does; lower() and
+	// LIKE in SQLite only fold ASCII letters.
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			switch v := args[0].(type) {
+			case string:
+				return strings.ToLower(v), nil
+			case []byte:
+				return strings.ToLower(string(v)), nil
+			}
+			return args[0], nil
+		})
+}
+
+var ErrDatabaseVersion = errors.New("storage: database schema is newer than supported")
+
+// sqliteMigrations[n] upgrades the database from PRAGMA user_version n to n+1.
+// Released steps must never change; new columns and tables get a new step.
+var sqliteMigrations = []func(tx *sql.Tx) error{
+	createSchema,
+	addSummaryColumns,
+}
+
+func createSchema(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+CREATE TABLE IF NOT EXISTS surveys (
+	id         TEXT PRIMARY KEY,
+	imo        TEXT NOT NULL DEFAULT '',
+	date       TEXT NOT NULL DEFAULT '',
+	job_number INTEGER NOT NULL DEFAULT 0,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS surveys_imo ON surveys (imo);
+CREATE INDEX IF NOT EXISTS surveys_date ON surveys (date);
+CREATE INDEX IF NOT EXISTS surveys_job_number ON surveys (job_number);
+
+CREATE TABLE IF NOT EXISTS users (
+	id   INTEGER PRIMARY KEY CHECK (id = 1),
+	data TEXT NOT NULL
+);
+`)
+	return err
+}
+
+// addSummaryColumns adds the columns filtered by Query and fills them from the
+// stored surveys. Rows that do not decode keep empty summaries.
+func addSummaryColumns(tx *sql.Tx) error {
+	existing, err := sqliteColumns(tx, "surveys")
+	if err != nil {
+		return err
+	}
+	for _, column := range []string{"kind", "status", "vessel_name", "port", "principal", "cargo"} {
+		if existing[column] {
+			continue
+		}
+		if _, err = tx.Exec(`ALTER TABLE surveys ADD COLUMN ` + column + ` TEXT NOT NULL DEFAULT ''`); err != nil {
+			return err
+		}
+	}
+
+	rows, err := tx.Query(`SELECT id, data FROM surveys`)
+	if err != nil {
+		return err
+	}
+	summaries := map[string]SurveySummary{}
+	for rows.Next() {
+		var id, data string
+		if err = rows.Scan(&id, &data); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if survey, _, derr := decodeSurvey([]byte(data)); derr == nil {
+			summaries[id] = summarize(id, survey)
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for id, sum := range summaries {
+		if _, err = tx.Exec(
+			`UPDATE surveys SET kind = ?, status = ?, vessel_name = ?, port = ?, principal = ?, cargo = ? WHERE id = ?`,
+			sum.Kind, sum.Status, sum.VesselName, sum.Port, sum.Principal, sum.Cargo, id,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sqliteColumns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// migrateSQLite brings the database up to the latest schema in a single
+// transaction.
+func migrateSQLite(db *sql.DB) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var version int
+	if err = tx.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("%w: %d", ErrDatabaseVersion, version)
+	}
+	if version == len(sqliteMigrations) {
+		return tx.Rollback()
+	}
+	for ; version < len(sqliteMigrations); version++ {
+		if err = sqliteMigrations[version](tx); err != nil {
+			return fmt.Errorf("storage: migrating database from version %d: %w", version, err)
+		}
+	}
+	if _, err = tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqlitePragmas make concurrent writers wait for each other, as JSONStore
+// writers wait for the survey lock, instead of failing with SQLITE_BUSY.
+var sqlitePragmas = fmt.Sprintf(
+	"?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_txlock=immediate",
+	DefaultLockTimeout.Milliseconds())
+
+// OpenSQLite opens the database file at path, creating it when missing and
+// migrating its schema to the latest version. The returned handle is shared by
+// SQLiteStore and SQLiteUserStore.
+func OpenSQLite(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path+sqlitePragmas)
+	if err != nil {
+		return nil, err
+	}
+	if err = migrateSQLite(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// notFound wraps fs.ErrNotExist so callers can treat a missing row the same
+// way as a missing file in JSONStore.
+func notFound(id string) error {
+	return fmt.Errorf("storage: survey %q: %w", id, fs.ErrNotExist)
+}
+
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+type SQLiteStore struct {
+	DB *sql.DB
+}
+
+// Create saves a new survey under a generated ID, which is also stored in
+// survey.ID, and returns it.
+func (s SQLiteStore) Create(survey *types.Survey) (string, error) {
+	id := NewID()
+	survey.ID = id
+	if err := s.Save(id, survey); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s SQLiteStore) Save(id string, survey *types.Survey) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	var data bytes.Buffer
+	if err := encodeSurvey(&data, survey); err != nil {
+		return err
+	}
+
+	sum := summarize(id, survey)
+	_, err := s.DB.Exec(
+		`INSERT INTO surveys (id, kind, status, vessel_name, imo, port, principal, cargo, date, job_number, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			kind = excluded.kind, status = excluded.status, vessel_name = excluded.vessel_name,
+			imo = excluded.imo, port = excluded.port, principal = excluded.principal, cargo = excluded.cargo,
+			date = excluded.date, job_number = excluded.job_number, data = excluded.data`,
+		id, sum.Kind, sum.Status, sum.VesselName, sum.IMO, sum.Port, sum.Principal, sum.Cargo,
+		sqliteTime(sum.Date), sum.JobNumber, data.String(),
+	)
+	return err
+}
+
+// sqliteTime formats t so that text comparison in SQL orders by time. The
+// zero time is stored as an empty string.
+func sqliteTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+func (s SQLiteStore) Get(id string) (*types.Survey, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	var data string
+	err := s.DB.QueryRow(`SELECT data FROM surveys WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	survey, _, err := decodeSurvey([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	return survey, nil
+}
+
+// GetAll decodes every stored survey. Rows that do not decode are skipped and
+// reported in a *CorruptFilesError returned with the rest.
+func (s SQLiteStore) GetAll() (surveys []*types.Survey, err error) {
+	rows, err := s.DB.Query(`SELECT id, data FROM surveys ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	corrupt := &CorruptFilesError{}
+	for rows.Next() {
+		var id, data string
+		if err = rows.Scan(&id, &data); err != nil {
+			return nil, err
+		}
+		survey, _, uerr := decodeSurvey([]byte(data))
+		if uerr != nil {
+			corrupt.add(id, uerr)
+			continue
+		}
+		surveys = append(surveys, survey)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return surveys, corrupt.result()
+}
+
+func (s SQLiteStore) Delete(id string) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	res, err := s.DB.Exec(`DELETE FROM surveys WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound(id)
+	}
+	return nil
+}
+
+func likePattern(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(value))
+	return "%" + value + "%"
+}
+
+func (s SQLiteStore) Query(q SurveyQuery) (page SurveyPage, err error) {
+	var where []string
+	var args []any
+	like := func(expr, value string) {
+		if value != "" {
+			where = append(where, expr)
+			for range strings.Count(expr, "?") {
+				args = append(args, likePattern(value))
+			}
+		}
+	}
+	like(`(unicode_lower(vessel_name) LIKE ? ESCAPE '\' OR unicode_lower(imo) LIKE ? ESCAPE '\')`, q.Vessel)
+	like(`unicode_lower(port) LIKE ? ESCAPE '\'`, q.Port)
+	like(`unicode_lower(principal) LIKE ? ESCAPE '\'`, q.Principal)
+	like(`unicode_lower(cargo) LIKE ? ESCAPE '\'`, q.Cargo)
+	if !q.From.IsZero() {
+		where = append(where, `date >= ?`)
+		args = append(args, sqliteTime(q.From))
+	}
+	if !q.To.IsZero() {
+		where = append(where, `date <= ?`)
+		args = append(args, sqliteTime(q.To))
+	}
+	if q.Status != "" {
+		where = append(where, `status = ?`)
+		args = append(args, q.Status)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	if err = s.DB.QueryRow(`SELECT COUNT(*) FROM surveys`+clause, args...).Scan(&page.Total); err != nil {
+		return SurveyPage{}, err
+	}
+
+	order := "date"
+	if q.Sort == SortByJobNumber {
+		order = "job_number"
+	}
+	dir := "ASC"
+	if q.Descending {
+		dir = "DESC"
+	}
+	limit := -1
+	if q.Limit > 0 {
+		limit = q.Limit
+	}
+	rows, err := s.DB.Query(
+		`SELECT id, kind, status, vessel_name, imo, port, principal, cargo, date, job_number FROM surveys`+
+			clause+` ORDER BY `+order+` `+dir+`, id `+dir+` LIMIT ? OFFSET ?`,
+		append(args, limit, max(q.Offset, 0))...,
+	)
+	if err != nil {
+		return SurveyPage{}, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var sum SurveySummary
+		var date string
+		if err = rows.Scan(
+			&sum.ID, &sum.Kind, &sum.Status, &sum.VesselName, &sum.IMO, &sum.Port, &sum.Principal, &sum.Cargo,
+			&date, &sum.JobNumber,
+		); err != nil {
+			return SurveyPage{}, err
+		}
+		if date != "" {
+			if sum.Date, err = time.Parse(sqliteTimeFormat, date); err != nil {
+				return SurveyPage{}, err
+			}
+		}
+		page.Summaries = append(page.Summaries, sum)
+	}
+	return page, rows.Err()
+}
+
+// SQLiteUserStore keeps the single local surveyor profile, the counterpart of
+// user.json in UserStore.
+type SQLiteUserStore struct {
+	DB *sql.DB
+}
+
+func (u SQLiteUserStore) Save(user *types.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	_, err = u.DB.Exec(
+		`INSERT INTO users (id, data) VALUES (1, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+		string(data),
+	)
+	return err
+}
+
+func (u SQLiteUserStore) Get() (*types.User, error) {
+	var data string
+	err := u.DB.QueryRow(`SELECT data FROM users WHERE id = 1`).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("storage: user: %w", fs.ErrNotExist)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user := &types.User{}
+	if err = json.Unmarshal([]byte(data), user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u SQLiteUserStore) Delete() error {
+	res, err := u.DB.Exec(`DELETE FROM users WHERE id = 1`)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("storage: user: %w", fs.ErrNotExist)
+	}
+	return nil
+}
@@ -1,15 +1,28 @@
 package storage
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"reflect"
-	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/AVZotov/draft-survey/internal/types"
 	"github.com/AVZotov/draft-survey/internal/vessel"
 )
 
-const id = "123-456-789"
+const id = "3f2b8c1e-6a4d-4e5f-9b7a-1c2d3e4f5a6b"
+
+func getID(i int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+}
 
 func getSurvey() *types.Survey {
 	return &types.Survey{
@@ -52,13 +65,409 @@ func getUser() *types.User {
 	}
 }
 
-func TestJSONStore_SaveAndGet(t *testing.T) {
+func surveyStores(t *testing.T) map[string]SurveyRepository {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "surveys.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return map[string]SurveyRepository{
+		"JSONStore":   JSONStore{Path: dir, TempPath: dir},
+		"SQLiteStore": SQLiteStore{DB: db},
+	}
+}
+
+func userStores(t *testing.T) map[string]UserRepository {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "surveys.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return map[string]UserRepository{
+		"UserStore":       UserStore{Path: dir},
+		"SQLiteUserStore": SQLiteUserStore{DB: db},
+	}
+}
+
+func TestSurveyRepository_SaveAndGet(t *testing.T) {
+	for name, store := range surveyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			surveyExpected := getSurvey()
+			if err := store.Save(id, surveyExpected); err != nil {
+				t.Fatal(err)
+			}
+			surveyGot, err := store.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(surveyExpected, surveyGot) {
+				t.Errorf("Expected %v, got %v", surveyExpected, surveyGot)
+			}
+		})
+	}
+}
+
+func TestSurveyRepository_Delete(t *testing.T) {
+	for name, store := range surveyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			surveyExpected := getSurvey()
+			if err := store.Save(id, surveyExpected); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.Delete(id); err != nil {
+				t.Fatal(err)
+			}
+
+			surveyGot, err := store.Get(id)
+			if err == nil {
+				t.Errorf("Expected error, got %#v", surveyGot)
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Expected %v, got %v", fs.ErrNotExist, err)
+			}
+		})
+	}
+}
+
+func TestSurveyRepository_GetAll(t *testing.T) {
+	for name, store := range surveyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			surveysExpected := getSurveys()
+			for i, survey := range surveysExpected {
+				if err := store.Save(getID(i), survey); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			surveysGot, err := store.GetAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(surveysExpected) != len(surveysGot) {
+				t.Fatalf("Slice length expected %d, got %d", len(surveysExpected), len(surveysGot))
+			}
+
+			for i, survey := range surveysExpected {
+				if !reflect.DeepEqual(survey, surveysGot[i]) {
+					t.Errorf("Expected %v, got %v", surveysExpected[i], surveysGot[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteStore_SaveOverwrites(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "surveys.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := SQLiteStore{DB: db}
+	survey := getSurvey()
+	if err := store.Save(id, survey); err != nil {
+		t.Fatal(err)
+	}
+	survey.Job.JobNumber = 654321
+	survey.VesselData.IMO = "9876543"
+	if err := store.Save(id, survey); err != nil {
+		t.Fatal(err)
+	}
+
+	var imo string
+	var jobNumber int
+	if err := db.QueryRow(`SELECT imo, job_number FROM surveys WHERE id = ?`, id).Scan(&imo, &jobNumber); err != nil {
+		t.Fatal(err)
+	}
+	if imo != "9876543" || jobNumber != 654321 {
+		t.Errorf("Expected indexed columns 9876543/654321, got %s/%d", imo, jobNumber)
+	}
+	all, err := store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Errorf("Expected 1 survey, got %d", len(all))
+	}
+}
+
+func TestOpenSQLite_MigratesVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "surveys.db")
+	old, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data bytes.Buffer
+	if err := encodeSurvey(&data, getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(`
+CREATE TABLE surveys (
+	id         TEXT PRIMARY KEY,
+	imo        TEXT NOT NULL DEFAULT '',
+	date       TEXT NOT NULL DEFAULT '',
+	job_number INTEGER NOT NULL DEFAULT 0,
+	data       TEXT NOT NULL
+);
+CREATE TABLE users (
+	id   INTEGER PRIMARY KEY CHECK (id = 1),
+	data TEXT NOT NULL
+);
+INSERT INTO surveys (id, data) VALUES (?, ?);`, id, data.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := old.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(sqliteMigrations) {
+		t.Errorf("Expected user_version %d, got %d", len(sqliteMigrations), version)
+	}
+
+	store := SQLiteStore{DB: db}
+	page, err := store.Query(SurveyQuery{Port: "testport"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 {
+		t.Errorf("Expected the existing survey found by port, got %d", page.Total)
+	}
+	if err := store.Save(getID(1), getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenSQLite_NewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "surveys.db")
+	db, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`PRAGMA user_version = 99`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenSQLite(path); !errors.Is(err, ErrDatabaseVersion) {
+		t.Errorf("Expected %v, got %v", ErrDatabaseVersion, err)
+	}
+}
+
+func TestUserRepository_SaveAndGet(t *testing.T) {
+	for name, store := range userStores(t) {
+		t.Run(name, func(t *testing.T) {
+			userExpected := getUser()
+			if err := store.Save(userExpected); err != nil {
+				t.Fatal(err)
+			}
+			userGot, err := store.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(userExpected, userGot) {
+				t.Errorf("Expected %v, got %v", userExpected, userGot)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetWithNoUser(t *testing.T) {
+	for name, store := range userStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Get()
+			if err == nil {
+				t.Fatal("User store should not have any user")
+			}
+		})
+	}
+}
+
+func TestUserRepository_Delete(t *testing.T) {
+	for name, store := range userStores(t) {
+		t.Run(name, func(t *testing.T) {
+			userExpected := getUser()
+			if err := store.Save(userExpected); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.Delete(); err != nil {
+				t.Fatal(err)
+			}
+
+			userGot, err := store.Get()
+			if err == nil {
+				t.Errorf("Expected error, got %#v", userGot)
+			}
+		})
+	}
+}
+
+func TestJSONStore_SaveLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := JSONStore{Path: dir, TempPath: dir}
+	for range 2 {
+		if err := store.Save(id, getSurvey()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if isTempFile(file.Name()) {
+			t.Errorf("Temporary file %s left behind", file.Name())
+		}
+	}
+}
+
+func TestJSONStore_Recover(t *testing.T) {
 	dir := t.TempDir()
-	surveyExpected := getSurvey()
 	store := JSONStore{Path: dir, TempPath: dir}
-	if err := store.Save(id, surveyExpected); err != nil {
+	if err := store.Save(id, getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "truncated.json"), []byte(`{"Job": {"JobNum`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".other.json.tmp-123"), []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	quarantined, err := store.Recover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quarantined) != 2 {
+		t.Fatalf("Expected 2 quarantined files, got %v", quarantined)
+	}
+	for _, path := range quarantined {
+		if filepath.Dir(path) != filepath.Join(dir, QuarantineDir) {
+			t.Errorf("Expected %s in quarantine directory", path)
+		}
+	}
+
+	surveys, err := store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(surveys) != 1 {
+		t.Errorf("Expected 1 survey after recovery, got %d", len(surveys))
+	}
+}
+
+func TestJSONStore_RecoverSkipsLockedTemp(t *testing.T) {
+	dir := t.TempDir()
+	store := JSONStore{Path: dir, TempPath: dir}
+	temp := filepath.Join(dir, "."+id+".json.tmp-123")
+	if err := os.WriteFile(temp, []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := store.lock(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quarantined, err := store.Recover()
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quarantined) != 0 {
+		t.Errorf("Expected no quarantined files while the survey is locked, got %v", quarantined)
+	}
+	if _, err := os.Stat(temp); err != nil {
+		t.Errorf("Temp file of a locked survey should be left alone: %v", err)
+	}
+
+	if quarantined, err = store.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if len(quarantined) != 1 {
+		t.Errorf("Expected 1 quarantined file, got %v", quarantined)
+	}
+}
+
+func TestUserStore_Recover(t *testing.T) {
+	dir := t.TempDir()
+	store := UserStore{Path: dir}
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(`{"LastName": "Do`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	quarantined, err := store.Recover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quarantined) != 1 {
+		t.Fatalf("Expected 1 quarantined file, got %v", quarantined)
+	}
+	if _, err := store.Get(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
+
+func getDraftStore(t *testing.T) JSONStore {
+	t.Helper()
+	return JSONStore{Path: t.TempDir(), TempPath: filepath.Join(t.TempDir(), "temp")}
+}
+
+func TestJSONStore_Drafts(t *testing.T) {
+	store := getDraftStore(t)
+	drafts, err := store.Drafts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 0 {
+		t.Fatalf("Expected no drafts, got %v", drafts)
+	}
+
+	if err := store.SaveDraft(id, getSurvey()); err != nil {
 		t.Fatal(err)
 	}
+	drafts, err = store.Drafts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 1 || drafts[0].ID != id || drafts[0].SavedAt.IsZero() {
+		t.Fatalf("Expected draft %s with timestamp, got %v", id, drafts)
+	}
+	if _, err := store.Get(id); err == nil {
+		t.Error("Draft should not be visible as a saved survey")
+	}
+}
+
+func TestJSONStore_PromoteDraft(t *testing.T) {
+	store := getDraftStore(t)
+	surveyExpected := getSurvey()
+	if err := store.SaveDraft(id, surveyExpected); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PromoteDraft(id); err != nil {
+		t.Fatal(err)
+	}
+
 	surveyGot, err := store.Get(id)
 	if err != nil {
 		t.Fatal(err)
@@ -66,90 +475,701 @@ func TestJSONStore_SaveAndGet(t *testing.T) {
 	if !reflect.DeepEqual(surveyExpected, surveyGot) {
 		t.Errorf("Expected %v, got %v", surveyExpected, surveyGot)
 	}
+	drafts, err := store.Drafts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 0 {
+		t.Errorf("Expected draft to be removed, got %v", drafts)
+	}
 }
 
-func TestJSONStore_Delete(t *testing.T) {
+func TestJSONStore_DeleteDraft(t *testing.T) {
+	store := getDraftStore(t)
+	if err := store.SaveDraft(id, getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteDraft(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteDraft(id); err != nil {
+		t.Errorf("Deleting a missing draft: expected nil, got %v", err)
+	}
+}
+
+func TestJSONStore_DraftsSharedPath(t *testing.T) {
 	dir := t.TempDir()
-	surveyExpected := getSurvey()
 	store := JSONStore{Path: dir, TempPath: dir}
-	if err := store.Save(id, surveyExpected); err != nil {
+	if err := store.SaveDraft(id, getSurvey()); err != ErrTempPath {
+		t.Errorf("Expected %v, got %v", ErrTempPath, err)
+	}
+}
+
+func TestAutoSaver_Run(t *testing.T) {
+	store := getDraftStore(t)
+	survey := getSurvey()
+	saver := AutoSaver{
+		Store:    store,
+		ID:       id,
+		Interval: time.Millisecond,
+		Current:  func() *types.Survey { return survey },
+		OnError:  func(err error) { t.Error(err) },
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	saver.Run(ctx)
+
+	surveyGot, err := store.GetDraft(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(survey, surveyGot) {
+		t.Errorf("Expected %v, got %v", survey, surveyGot)
+	}
+}
+
+func TestAutoSaver_ZeroValue(t *testing.T) {
+	store := getDraftStore(t)
+	survey := getSurvey()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	AutoSaver{Store: store, ID: id}.Run(ctx)
+	if drafts, err := store.Drafts(); err != nil || len(drafts) != 0 {
+		t.Errorf("Expected no drafts, got %v (%v)", drafts, err)
+	}
+
+	AutoSaver{Store: store, ID: id, Current: func() *types.Survey { return survey }}.Run(ctx)
+	if _, err := store.GetDraft(id); err != nil {
+		t.Errorf("Expected draft saved on cancel, got %v", err)
+	}
+}
+
+func getBackupStore(t *testing.T, generations int) JSONStore {
+	t.Helper()
+	return JSONStore{
+		Path:              t.TempDir(),
+		TempPath:          filepath.Join(t.TempDir(), "temp"),
+		BackupPath:        filepath.Join(t.TempDir(), "backups"),
+		BackupGenerations: generations,
+	}
+}
+
+func TestJSONStore_BackupRotation(t *testing.T) {
+	store := getBackupStore(t, 2)
+	survey := getSurvey()
+	for i := range 3 {
+		survey.Job.JobNumber = i
+		if err := store.Save(id, survey); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := store.Backups(id)
+	if err != nil {
 		t.Fatal(err)
 	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 generations, got %d", len(backups))
+	}
+	if !backups[0].After(backups[1]) {
+		t.Errorf("Expected newest first, got %v", backups)
+	}
+}
 
-	if err := store.Delete(id); err != nil {
+func TestJSONStore_Restore(t *testing.T) {
+	store := getBackupStore(t, 0)
+	survey := getSurvey()
+	if err := store.Save(id, survey); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := store.Backups(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := getSurvey()
+	changed.Job.JobNumber = 1
+	if err := store.Save(id, changed); err != nil {
 		t.Fatal(err)
 	}
 
+	if err := store.Restore(id, backups[0]); err != nil {
+		t.Fatal(err)
+	}
 	surveyGot, err := store.Get(id)
-	if err == nil {
-		t.Errorf("Expected error, got %#v", surveyGot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(survey, surveyGot) {
+		t.Errorf("Expected %v, got %v", survey, surveyGot)
+	}
+
+	if err := store.Restore(id, time.Unix(0, 0)); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected %v, got %v", ErrBackupNotFound, err)
 	}
 }
 
-func TestJSONStore_GetAll(t *testing.T) {
-	dir := t.TempDir()
+func TestJSONStore_ArchiveAndRestore(t *testing.T) {
+	store := getBackupStore(t, 0)
 	surveysExpected := getSurveys()
-	store := JSONStore{Path: dir, TempPath: dir}
-
 	for i, survey := range surveysExpected {
-		if err := store.Save(id+strconv.Itoa(i), survey); err != nil {
+		if err := store.Save(getID(i), survey); err != nil {
+			t.Fatal(err)
 		}
 	}
+	var archive bytes.Buffer
+	if err := store.Archive(&archive); err != nil {
+		t.Fatal(err)
+	}
 
-	surveysGot, err := store.GetAll()
+	restored := getBackupStore(t, 0)
+	if err := restored.RestoreArchive(&archive); err != nil {
+		t.Fatal(err)
+	}
+	surveysGot, err := restored.GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(surveysExpected) != len(surveysGot) {
-		t.Errorf("Slice length expected %d, got %d", len(surveysExpected), len(surveysGot))
+	if !reflect.DeepEqual(surveysExpected, surveysGot) {
+		t.Errorf("Expected %v, got %v", surveysExpected, surveysGot)
 	}
+	backups, err := restored.Backups(getID(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Errorf("Expected 1 restored backup, got %d", len(backups))
+	}
+}
 
-	for i, survey := range surveysExpected {
-		if !reflect.DeepEqual(survey, surveysGot[i]) {
-			t.Errorf("Expected %v, got %v", surveysExpected[i], surveysGot[i])
+func getQuerySurveys() map[string]*types.Survey {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 8, 0, 0, 0, time.UTC) }
+	surveys := map[string]*types.Survey{}
+	for i, v := range []struct {
+		vessel, imo, port, principal, cargo string
+		job, day                            int
+		status                              types.SurveyStatus
+	}{
+		{"Ocean Star", "9111111", "Novorossiysk", "Cargill", "Wheat", 30, 4, types.SurveyStatusReported},
+		{"Ocean Pearl", "9222222", "Tuapse", "Bunge", "Barley", 10, 1, types.SurveyStatusOpen},
+		{"Baltic Wind", "9333333", "Novorossiysk", "Cargill", "Corn", 20, 3, types.SurveyStatusReported},
+		{"ПОЛЯРНАЯ ЗВЕЗДА", "9444444", "Ust-Luga", "Glencore", "Wheat 100%", 40, 2, types.SurveyStatusOpen},
+	} {
+		s := getSurvey()
+		s.VesselData.Name, s.VesselData.IMO = v.vessel, v.imo
+		s.CargoOperation.Port, s.Job.Principal, s.CargoOperation.Cargo = v.port, v.principal, v.cargo
+		s.Job.JobNumber = v.job
+		s.InitialDraft.StartedAt = day(v.day)
+		s.Status = v.status
+		surveys[getID(i)] = s
+	}
+	return surveys
+}
+
+func TestSurveyRepository_Query(t *testing.T) {
+	tests := []struct {
+		name  string
+		query SurveyQuery
+		ids   []string
+		total int
+	}{
+		{"all by date", SurveyQuery{}, []string{getID(1), getID(3), getID(2), getID(0)}, 4},
+		{"vessel name", SurveyQuery{Vessel: "ocean"}, []string{getID(1), getID(0)}, 2},
+		{"imo", SurveyQuery{Vessel: "9333"}, []string{getID(2)}, 1},
+		{"non-ascii vessel name", SurveyQuery{Vessel: "полярная"}, []string{getID(3)}, 1},
+		{"port and status", SurveyQuery{Port: "novo", Status: types.SurveyStatusReported}, []string{getID(2), getID(0)}, 2},
+		{"principal", SurveyQuery{Principal: "BUNGE"}, []string{getID(1)}, 1},
+		{"cargo wildcard", SurveyQuery{Cargo: "%"}, []string{getID(3)}, 1},
+		{
+			"date range",
+			SurveyQuery{From: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)},
+			[]string{getID(3), getID(2)}, 2,
+		},
+		{"job number descending", SurveyQuery{Sort: SortByJobNumber, Descending: true}, []string{getID(3), getID(0), getID(2), getID(1)}, 4},
+		{"page", SurveyQuery{Sort: SortByJobNumber, Offset: 1, Limit: 2}, []string{getID(2), getID(0)}, 4},
+		{"past the end", SurveyQuery{Offset: 10}, nil, 4},
+	}
+
+	for name, store := range surveyStores(t) {
+		for id, survey := range getQuerySurveys() {
+			if err := store.Save(id, survey); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				page, err := store.Query(tt.query)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var ids []string
+				for _, s := range page.Summaries {
+					ids = append(ids, s.ID)
+				}
+				if !reflect.DeepEqual(tt.ids, ids) {
+					t.Errorf("Expected %v, got %v", tt.ids, ids)
+				}
+				if tt.total != page.Total {
+					t.Errorf("Total: expected %d, got %d", tt.total, page.Total)
+				}
+			})
 		}
 	}
 }
 
-func TestUserStore_SaveAndGet(t *testing.T) {
+func TestSurveyRepository_QuerySummary(t *testing.T) {
+	surveys := getQuerySurveys()
+	expected := SurveySummary{
+		ID:         getID(0),
+		Status:     types.SurveyStatusReported,
+		VesselName: "Ocean Star",
+		IMO:        "9111111",
+		Port:       "Novorossiysk",
+		Principal:  "Cargill",
+		Cargo:      "Wheat",
+		JobNumber:  30,
+		Date:       time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC),
+	}
+	for name, store := range surveyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Save(getID(0), surveys[getID(0)]); err != nil {
+				t.Fatal(err)
+			}
+			page, err := store.Query(SurveyQuery{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Summaries) != 1 || !reflect.DeepEqual(expected, page.Summaries[0]) {
+				t.Errorf("Expected %v, got %v", expected, page.Summaries)
+			}
+
+			if err := store.Delete(getID(0)); err != nil {
+				t.Fatal(err)
+			}
+			if page, err = store.Query(SurveyQuery{}); err != nil || page.Total != 0 {
+				t.Errorf("Expected empty result after delete, got %v, %v", page, err)
+			}
+		})
+	}
+}
+
+func TestJSONStore_QueryRebuildsIndex(t *testing.T) {
 	dir := t.TempDir()
-	userExpected := getUser()
-	store := UserStore{Path: dir}
-	if err := store.Save(userExpected); err != nil {
+	store := JSONStore{Path: dir, TempPath: dir}
+	if err := store.Save(id, getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, IndexFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := store.Query(SurveyQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 || page.Summaries[0].ID != id {
+		t.Errorf("Expected %s from rebuilt index, got %v", id, page)
+	}
+	if _, err := os.Stat(filepath.Join(dir, IndexFile)); err != nil {
+		t.Errorf("Expected rebuilt index saved, got %v", err)
+	}
+}
+
+func TestJSONStore_GetAllSkipsCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	store := JSONStore{Path: dir, TempPath: dir}
+	if err := store.Save(id, getSurvey()); err != nil {
 		t.Fatal(err)
 	}
-	userGot, err := store.Get()
+	if err := os.WriteFile(filepath.Join(dir, ".DS_Store"), []byte{0, 1, 2}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"Job":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	surveys, err := store.GetAll()
+	if len(surveys) != 1 {
+		t.Errorf("Expected 1 survey, got %d", len(surveys))
+	}
+	var corrupt *CorruptFilesError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("Expected *CorruptFilesError, got %v", err)
+	}
+	if len(corrupt.Files) != 1 || corrupt.Files[0].File != "broken.json" {
+		t.Errorf("Expected broken.json to be reported, got %v", corrupt.Files)
+	}
+}
+
+func TestSQLiteStore_GetAllSkipsCorrupt(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "surveys.db"))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !reflect.DeepEqual(userExpected, userGot) {
-		t.Errorf("Expected %v, got %v", userExpected, userGot)
+	defer db.Close()
+
+	store := SQLiteStore{DB: db}
+	for _, id := range []string{getID(1), getID(2)} {
+		if err := store.Save(id, getSurvey()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE surveys SET data = '{' WHERE id = ?`, getID(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	surveys, err := store.GetAll()
+	if len(surveys) != 1 {
+		t.Errorf("Expected 1 survey, got %d", len(surveys))
+	}
+	var corrupt *CorruptFilesError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("Expected *CorruptFilesError, got %v", err)
+	}
+	if len(corrupt.Files) != 1 || corrupt.Files[0].File != getID(2) {
+		t.Errorf("Expected %s to be reported, got %v", getID(2), corrupt.Files)
 	}
 }
 
-func TestUserStore_GetWithNoUser(t *testing.T) {
+func TestSurveyRepository_InvalidID(t *testing.T) {
+	for name, store := range surveyStores(t) {
+		for _, bad := range []string{"../../user", "123-456-789", "", "3F2B8C1E-6A4D-4E5F-9B7A-1C2D3E4F5A6B"} {
+			t.Run(name+"/"+bad, func(t *testing.T) {
+				if err := store.Save(bad, getSurvey()); !errors.Is(err, ErrInvalidID) {
+					t.Errorf("Save: expected %v, got %v", ErrInvalidID, err)
+				}
+				if _, err := store.Get(bad); !errors.Is(err, ErrInvalidID) {
+					t.Errorf("Get: expected %v, got %v", ErrInvalidID, err)
+				}
+				if err := store.Delete(bad); !errors.Is(err, ErrInvalidID) {
+					t.Errorf("Delete: expected %v, got %v", ErrInvalidID, err)
+				}
+			})
+		}
+	}
+}
+
+func TestSurveyRepository_Create(t *testing.T) {
+	for name, store := range surveyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			survey := getSurvey()
+			id, err := store.Create(survey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateID(id); err != nil {
+				t.Fatal(err)
+			}
+			if survey.ID != id {
+				t.Errorf("Expected survey.ID %s, got %s", id, survey.ID)
+			}
+			surveyGot, err := store.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(survey, surveyGot) {
+				t.Errorf("Expected %v, got %v", survey, surveyGot)
+			}
+		})
+	}
+}
+
+func TestJSONStore_LockTimeout(t *testing.T) {
 	dir := t.TempDir()
-	store := UserStore{Path: dir}
-	_, err := store.Get()
-	if err == nil {
-		t.Fatal("User store should not have any user")
+	store := JSONStore{Path: dir, TempPath: dir, LockTimeout: 50 * time.Millisecond}
+	unlock, err := store.lock(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save(id, getSurvey()); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("Expected %v, got %v", ErrLockTimeout, err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(id, getSurvey()); err != nil {
+		t.Errorf("Expected save after unlock, got %v", err)
 	}
 }
 
-func TestUserStore_Delete(t *testing.T) {
+func TestSurveyRepository_ConcurrentSave(t *testing.T) {
+	const writers = 32
+	for name, store := range surveyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			errs := make(chan error, writers)
+			for i := range writers {
+				go func() {
+					survey := getSurvey()
+					survey.Job.JobNumber = i
+					errs <- store.Save(getID(i), survey)
+				}()
+			}
+			for range writers {
+				if err := <-errs; err != nil {
+					t.Error(err)
+				}
+			}
+
+			surveys, err := store.GetAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(surveys) != writers {
+				t.Errorf("Expected %d surveys, got %d", writers, len(surveys))
+			}
+		})
+	}
+}
+
+func TestJSONStore_ConcurrentSave(t *testing.T) {
 	dir := t.TempDir()
-	userExpected := getUser()
-	store := UserStore{Path: dir}
-	if err := store.Save(userExpected); err != nil {
+	store := JSONStore{Path: dir, TempPath: dir}
+	const writers = 8
+
+	errs := make(chan error, writers)
+	for i := range writers {
+		go func() {
+			survey := getSurvey()
+			survey.Job.JobNumber = i
+			errs <- store.Save(getID(i), survey)
+		}()
+	}
+	for range writers {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := store.Query(SurveyQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != writers {
+		t.Errorf("Expected %d indexed surveys, got %d", writers, page.Total)
+	}
+	surveys, err := store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(surveys) != writers {
+		t.Errorf("Expected %d surveys, got %d", writers, len(surveys))
+	}
+}
+
+func TestJSONStore_ConcurrentRewriteOnRead(t *testing.T) {
+	dir := t.TempDir()
+	store := JSONStore{Path: dir, TempPath: dir, RewriteOnRead: true}
+	path := filepath.Join(dir, goldenID+".json")
+	saved := getGoldenSurvey()
+	saved.Job.JobNumber = 999999
+
+	for range 20 {
+		if err := os.WriteFile(path, readFixture(t, 0), 0644); err != nil {
+			t.Fatal(err)
+		}
+		errs := make(chan error, 2)
+		go func() {
+			_, err := store.Get(goldenID)
+			errs <- err
+		}()
+		go func() {
+			errs <- store.Save(goldenID, saved)
+		}()
+		for range 2 {
+			if err := <-errs; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		surveyGot, _, err := store.readSurvey(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if surveyGot.Job.JobNumber != saved.Job.JobNumber {
+			t.Fatalf("Expected saved job number %d, got %d", saved.Job.JobNumber, surveyGot.Job.JobNumber)
+		}
+	}
+}
+
+func getEncryptedStores(t *testing.T, passphrase string) (string, JSONStore, UserStore) {
+	t.Helper()
+	data := t.TempDir()
+	c, err := OpenCipher(data, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	surveys := JSONStore{
+		Path:       filepath.Join(data, "surveys"),
+		TempPath:   filepath.Join(data, "temp"),
+		BackupPath: filepath.Join(data, "backups"),
+		Cipher:     c,
+	}
+	if err := os.MkdirAll(surveys.Path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return data, surveys, UserStore{Path: data, Cipher: c}
+}
+
+func assertNoPlainText(t *testing.T, dir, text string) {
+	t.Helper()
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() == KeyFile || strings.HasSuffix(d.Name(), ".lock") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, []byte(text)) {
+			t.Errorf("%s contains %q in plain text", p, text)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJSONStore_Encrypted(t *testing.T) {
+	data, surveys, users := getEncryptedStores(t, "correct horse")
+	surveyExpected := getSurvey()
+	if err := surveys.Save(id, surveyExpected); err != nil {
+		t.Fatal(err)
+	}
+	if err := surveys.SaveDraft(id, surveyExpected); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(getUser()); err != nil {
+		t.Fatal(err)
+	}
+	assertNoPlainText(t, data, "testPrincipal")
+	assertNoPlainText(t, data, "Dow")
+
+	surveyGot, err := surveys.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(surveyExpected, surveyGot) {
+		t.Errorf("Expected %v, got %v", surveyExpected, surveyGot)
+	}
+	if page, err := surveys.Query(SurveyQuery{Principal: "testPrincipal"}); err != nil || page.Total != 1 {
+		t.Errorf("Expected 1 match from encrypted index, got %v, %v", page, err)
+	}
+
+	plain := JSONStore{Path: surveys.Path, TempPath: surveys.TempPath}
+	if _, err := plain.Get(id); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Expected %v, got %v", ErrEncrypted, err)
+	}
+	if _, err := plain.Recover(); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Recover: expected %v, got %v", ErrEncrypted, err)
+	}
+	if _, err := os.Stat(filepath.Join(surveys.Path, id+".json")); err != nil {
+		t.Errorf("Encrypted survey should not be quarantined: %v", err)
+	}
+}
+
+func TestOpenCipher_WrongPassphrase(t *testing.T) {
+	data := t.TempDir()
+	if _, err := OpenCipher(data, "correct horse"); err != nil {
 		t.Fatal(err)
 	}
+	if _, err := OpenCipher(data, "battery staple"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Expected %v, got %v", ErrWrongPassphrase, err)
+	}
+}
 
-	if err := store.Delete(); err != nil {
+func TestChangePassphrase(t *testing.T) {
+	data, surveys, users := getEncryptedStores(t, "correct horse")
+	surveyExpected := getSurvey()
+	if err := surveys.Save(id, surveyExpected); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(getUser()); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := surveys.Backups(id)
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	userGot, err := store.Get()
-	if err == nil {
-		t.Errorf("Expected error, got %#v", userGot)
+	if err := ChangePassphrase(data, "wrong", "battery staple", surveys, users); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("Expected %v, got %v", ErrWrongPassphrase, err)
+	}
+	if err := ChangePassphrase(data, "correct horse", "battery staple", surveys, users); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenCipher(data, "correct horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Old passphrase: expected %v, got %v", ErrWrongPassphrase, err)
+	}
+	if _, err := surveys.Get(id); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Old key: expected %v, got %v", ErrWrongKey, err)
+	}
+	if err := surveys.Save(id, surveyExpected); !errors.Is(err, ErrKeyChanged) {
+		t.Errorf("Save with old key: expected %v, got %v", ErrKeyChanged, err)
+	}
+	if err := users.Save(getUser()); !errors.Is(err, ErrKeyChanged) {
+		t.Errorf("User save with old key: expected %v, got %v", ErrKeyChanged, err)
+	}
+
+	c, err := OpenCipher(data, "battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	surveys.Cipher, users.Cipher = c, c
+	surveyGot, err := surveys.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(surveyExpected, surveyGot) {
+		t.Errorf("Expected %v, got %v", surveyExpected, surveyGot)
+	}
+	if _, err := users.Get(); err != nil {
+		t.Errorf("User: %v", err)
+	}
+	if err := surveys.Restore(id, backups[0]); err != nil {
+		t.Errorf("Restore from re-encrypted backup: %v", err)
+	}
+}
+
+func TestJSONStore_ReencryptPlainStore(t *testing.T) {
+	data, surveys, _ := getEncryptedStores(t, "correct horse")
+	plain := surveys
+	plain.Cipher = nil
+	if err := plain.Save(id, getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := surveys.Reencrypt(); err != nil {
+		t.Fatal(err)
+	}
+	assertNoPlainText(t, data, "testPrincipal")
+	if _, err := surveys.Get(id); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSONStore_RestoreEncryptedArchiveWithoutKey(t *testing.T) {
+	_, surveys, _ := getEncryptedStores(t, "correct horse")
+	if err := surveys.Save(id, getSurvey()); err != nil {
+		t.Fatal(err)
+	}
+	var archive bytes.Buffer
+	if err := surveys.Archive(&archive); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := JSONStore{Path: t.TempDir(), TempPath: t.TempDir(), BackupPath: t.TempDir()}
+	if err := restored.RestoreArchive(&archive); err != nil {
+		t.Fatal(err)
+	}
+	restored.Cipher = surveys.Cipher
+	page, err := restored.Query(SurveyQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 {
+		t.Errorf("Expected 1 survey after restore, got %d", page.Total)
 	}
 }
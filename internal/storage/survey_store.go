@@ -1,10 +1,14 @@
 package storage
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/AVZotov/draft-survey/internal/types"
 )
@@ -14,70 +18,290 @@ var _ SurveyRepository = (*JSONStore)(nil)
 type JSONStore struct {
 	Path     string
 	TempPath string
+	// BackupPath, when set, receives a timestamped snapshot of every saved
+	// survey; BackupGenerations of them are kept per survey.
+	BackupPath        string
+	BackupGenerations int
+	// RewriteOnRead upgrades survey files stored in an older schema version
+	// when they are read.
+	RewriteOnRead bool
+	// LockTimeout bounds the wait for another process or goroutine holding
+	// the same survey or the index.
+	LockTimeout time.Duration
+	// Cipher, when set, encrypts surveys, drafts, backups and the index.
+	Cipher *Cipher
+}
+
+func (j JSONStore) surveyPath(id string) (string, error) {
+	if err := ValidateID(id); err != nil {
+		return "", err
+	}
+	return filepath.Join(j.Path, id+".json"), nil
+}
+
+// Create saves a new survey under a generated ID, which is also stored in
+// survey.ID, and returns it.
+func (j JSONStore) Create(survey *types.Survey) (string, error) {
+	id := NewID()
+	survey.ID = id
+	if err := j.Save(id, survey); err != nil {
+		return "", err
+	}
+	return id, nil
 }
 
 func (j JSONStore) Save(id string, survey *types.Survey) error {
-	filename := id + ".json"
-	path := filepath.Join(j.Path, filename)
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	path, err := j.surveyPath(id)
 	if err != nil {
 		return err
 	}
-	defer func(file *os.File) {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = cerr
+	return j.withLock(id, func() error {
+		if err := j.writeSurvey(path, survey); err != nil {
+			return err
 		}
-	}(file)
-
-	encoder := json.NewEncoder(file)
-	if err = encoder.Encode(survey); err != nil {
-		return err
-	}
-	return nil
+		if err := j.indexSurvey(id, survey); err != nil {
+			return err
+		}
+		if j.BackupPath == "" {
+			return nil
+		}
+		return j.backup(id)
+	})
 }
 
+// Get reads a survey of any supported schema version. With RewriteOnRead set,
+// a survey stored in an older version is written back in the current one.
 func (j JSONStore) Get(id string) (*types.Survey, error) {
-	filename := id + ".json"
-	path := filepath.Join(j.Path, filename)
-	file, err := os.Open(path)
+	path, err := j.surveyPath(id)
 	if err != nil {
 		return nil, err
 	}
-	defer func(file *os.File) {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}(file)
-
-	decoder := json.NewDecoder(file)
-	survey := &types.Survey{}
-	if err = decoder.Decode(survey); err != nil {
+	survey, version, err := j.readSurvey(path)
+	if err != nil {
 		return nil, err
 	}
 
+	if j.RewriteOnRead && version < SchemaVersion {
+		// Read again under the lock so a Save made since the first read is
+		// not overwritten with the older content.
+		if err = j.withLock(id, func() error {
+			current, version, err := j.readSurvey(path)
+			if err != nil {
+				return err
+			}
+			survey = current
+			if version >= SchemaVersion {
+				return nil
+			}
+			return j.writeSurvey(path, current)
+		}); err != nil {
+			return nil, err
+		}
+	}
 	return survey, nil
 }
 
+func (j JSONStore) readSurvey(path string) (*types.Survey, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if data, err = j.Cipher.open(data); err != nil {
+		return nil, 0, err
+	}
+	return decodeSurvey(data)
+}
+
+func (j JSONStore) writeSurvey(path string, survey *types.Survey) error {
+	var buf bytes.Buffer
+	if err := encodeSurvey(&buf, survey); err != nil {
+		return err
+	}
+	data, err := j.Cipher.seal(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// GetAll reads every *.json survey in Path. Files that cannot be read are
+// skipped and reported in a *CorruptFilesError returned with the rest.
 func (j JSONStore) GetAll() ([]*types.Survey, error) {
 	var surveys []*types.Survey
 	files, err := os.ReadDir(j.Path)
 	if err != nil {
 		return nil, err
 	}
+	corrupt := &CorruptFilesError{}
 	for _, file := range files {
-		if file.IsDir() {
+		if !isSurveyFile(file) {
 			continue
 		}
 		id := strings.TrimSuffix(file.Name(), ".json")
 		survey, err := j.Get(id)
 		if err != nil {
-			return nil, err
+			corrupt.add(file.Name(), err)
+			continue
 		}
 		surveys = append(surveys, survey)
 	}
-	return surveys, nil
+	return surveys, corrupt.result()
 }
 
 func (j JSONStore) Delete(id string) error {
-	return os.Remove(filepath.Join(j.Path, id+".json"))
+	path, err := j.surveyPath(id)
+	if err != nil {
+		return err
+	}
+	return j.withLock(id, func() error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		return j.unindexSurvey(id)
+	})
+}
+
+// Recover is meant to run on startup. It moves leftover temporary files and
+// survey files that no longer decode into the quarantine directory and returns
+// their new paths. Files whose survey is locked by a writer are in use and
+// left alone. Files that only fail because of a missing or different key are
+// left alone and reported as an error.
+func (j JSONStore) Recover() ([]string, error) {
+	files, err := os.ReadDir(j.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var quarantined []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		name := file.Name()
+		switch {
+		case isTempFile(name):
+			base, _, _ := strings.Cut(strings.TrimPrefix(name, "."), tempMarker)
+			err = j.recoverFile(name, fileLock(base), false, &quarantined)
+		case isSurveyFile(file):
+			err = j.recoverFile(name, fileLock(name), true, &quarantined)
+		default:
+			continue
+		}
+		if err != nil {
+			return quarantined, err
+		}
+	}
+	if len(quarantined) > 0 {
+		if err = j.withFileLock(indexLock, func() error {
+			return os.Remove(j.indexPath())
+		}); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return quarantined, err
+		}
+	}
+	return quarantined, nil
+}
+
+// fileLock returns the lock guarding writes to the file called name in Path.
+func fileLock(name string) string {
+	switch name {
+	case IndexFile:
+		return indexLock
+	case KeyFile:
+		return keyLock
+	}
+	return strings.TrimSuffix(name, ".json")
+}
+
+// recoverFile quarantines the file called name unless its lock is held. With
+// check set, a file that still decodes is kept.
+func (j JSONStore) recoverFile(name, lock string, check bool, quarantined *[]string) error {
+	unlock, err := lockFile(j.Path, lock, true, 0)
+	if errors.Is(err, ErrLockTimeout) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return runLocked(unlock, func() error {
+		path := filepath.Join(j.Path, name)
+		if check {
+			_, _, err := j.readSurvey(path)
+			if err == nil || errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if keyError(err) {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		target, err := quarantine(j.Path, path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		*quarantined = append(*quarantined, target)
+		return nil
+	})
+}
+
+// Reencrypt rewrites every survey, the index, drafts and backups under the
+// current key of Cipher. It also encrypts a store that was kept in plain text
+// before a Cipher was configured. Writers are held off by the key lock.
+func (j JSONStore) Reencrypt() error {
+	unlock, err := j.Cipher.lock(true, j.lockTimeout())
+	if err != nil {
+		return err
+	}
+	return runLocked(unlock, j.reencrypt)
+}
+
+// reencrypt is Reencrypt for callers holding the key lock exclusively.
+func (j JSONStore) reencrypt() error {
+	files, err := os.ReadDir(j.Path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !isSurveyFile(file) {
+			continue
+		}
+		id := strings.TrimSuffix(file.Name(), ".json")
+		path := filepath.Join(j.Path, file.Name())
+		if err = j.withFileLock(id, func() error {
+			return j.Cipher.reencryptFile(path)
+		}); err != nil {
+			return err
+		}
+	}
+	if err = j.withFileLock(indexLock, func() error {
+		return j.Cipher.reencryptFile(j.indexPath())
+	}); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for _, dir := range []string{j.TempPath, j.BackupPath} {
+		if dir == "" || filepath.Clean(dir) == filepath.Clean(j.Path) {
+			continue
+		}
+		err = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				if p == dir && errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || isTempFile(d.Name()) || !strings.HasSuffix(d.Name(), ".json") {
+				return nil
+			}
+			return j.Cipher.reencryptFile(p)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -2,8 +2,12 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/AVZotov/draft-survey/internal/types"
 )
@@ -14,44 +18,43 @@ const fileName = "user.json"
 
 type UserStore struct {
 	Path string
+	// Cipher, when set, encrypts user.json.
+	Cipher *Cipher
 }
 
 func (u UserStore) Save(user *types.User) error {
-	path := filepath.Join(u.Path, fileName)
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	plain, err := json.Marshal(user)
 	if err != nil {
 		return err
 	}
-	defer func(file *os.File) {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}(file)
-
-	err = json.NewEncoder(file).Encode(user)
+	data, err := u.Cipher.seal(plain)
 	if err != nil {
 		return err
 	}
-
-	return nil
+	unlock, err := u.Cipher.hold(DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(u.Path, fileName)
+	return runLocked(unlock, func() error {
+		return writeFileAtomic(path, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+	})
 }
 
 func (u UserStore) Get() (*types.User, error) {
-	path := filepath.Join(u.Path, fileName)
-	file, err := os.Open(path)
+	data, err := os.ReadFile(filepath.Join(u.Path, fileName))
 	if err != nil {
 		return nil, err
 	}
-
-	defer func(file *os.File) {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}(file)
+	if data, err = u.Cipher.open(data); err != nil {
+		return nil, err
+	}
 
 	user := &types.User{}
-	err = json.NewDecoder(file).Decode(user)
-	if err != nil {
+	if err = json.Unmarshal(data, user); err != nil {
 		return nil, err
 	}
 
@@ -66,3 +69,59 @@ func (u UserStore) Delete() error {
 
 	return nil
 }
+
+// Recover quarantines a leftover temporary profile file, or user.json itself
+// when it no longer decodes, and returns the new paths.
+func (u UserStore) Recover() ([]string, error) {
+	files, err := os.ReadDir(u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var quarantined []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		name := file.Name()
+		path := filepath.Join(u.Path, name)
+		switch {
+		case isTempFile(name) && strings.HasPrefix(name, "."+fileName):
+		case name == fileName:
+			_, err := u.Get()
+			if err == nil {
+				continue
+			}
+			if keyError(err) {
+				return quarantined, fmt.Errorf("%s: %w", name, err)
+			}
+		default:
+			continue
+		}
+		target, err := quarantine(u.Path, path)
+		if err != nil {
+			return quarantined, err
+		}
+		quarantined = append(quarantined, target)
+	}
+	return quarantined, nil
+}
+
+// Reencrypt rewrites user.json under the current key of Cipher. Writers are
+// held off by the key lock.
+func (u UserStore) Reencrypt() error {
+	unlock, err := u.Cipher.lock(true, DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	return runLocked(unlock, u.reencrypt)
+}
+
+// reencrypt is Reencrypt for callers holding the key lock exclusively.
+func (u UserStore) reencrypt() error {
+	err := u.Cipher.reencryptFile(filepath.Join(u.Path, fileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,20 @@
+package types
+
+type TankChange struct {
+	Name        string
+	Initial     float64
+	Final       float64
+	Change      float64
+	OnlyInitial bool
+	OnlyFinal   bool
+}
+
+type DeductiblesChange struct {
+	Ballast          []TankChange
+	FreshWater       []TankChange
+	Deductibles      []TankChange
+	BallastTotal     float64
+	FreshWaterTotal  float64
+	DeductiblesTotal float64
+	Total            float64
+}
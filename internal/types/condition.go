@@ -0,0 +1,69 @@
+package types
+
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+type Condition struct {
+	BallastWaterTanks []BallastWaterTank
+	FreshWaterTanks   []FreshWaterTank
+	Deductibles       Deductibles
+	Marks             Marks
+	Density           units.Density
+	MTCRows           []MTCRow
+	HydrostaticRows   []HydrostaticRow
+	HydrostaticUnit   HydrostaticUnit
+	TPCListPort       float64
+	TPCListStarboard  float64
+	SeaCondition      SeaCondition
+}
+
+func (d InitialDraft) Condition() Condition {
+	return Condition{
+		BallastWaterTanks: d.BallastWaterTanks,
+		FreshWaterTanks:   d.FreshWaterTanks,
+		Deductibles:       d.Deductibles,
+		Marks:             d.Marks,
+		Density:           d.Density,
+		MTCRows:           d.MTCRows,
+		HydrostaticRows:   d.HydrostaticRows,
+		HydrostaticUnit:   d.HydrostaticUnit,
+		TPCListPort:       d.TPCListPort,
+		TPCListStarboard:  d.TPCListStarboard,
+		SeaCondition:      d.SeaCondition,
+	}
+}
+
+func (d FinalDraft) Condition() Condition {
+	return Condition{
+		BallastWaterTanks: d.BallastWaterTanks,
+		FreshWaterTanks:   d.FreshWaterTanks,
+		Deductibles:       d.Deductibles,
+		Marks:             d.Marks,
+		Density:           d.Density,
+		MTCRows:           d.MTCRows,
+		HydrostaticRows:   d.HydrostaticRows,
+		HydrostaticUnit:   d.HydrostaticUnit,
+		TPCListPort:       d.TPCListPort,
+		TPCListStarboard:  d.TPCListStarboard,
+		SeaCondition:      d.SeaCondition,
+	}
+}
+
+func (c Condition) Metric() Condition {
+	if c.HydrostaticUnit != HydrostaticUnitImperial {
+		return c
+	}
+
+	mtcRows := make([]MTCRow, len(c.MTCRows))
+	for i, r := range c.MTCRows {
+		mtcRows[i] = r.ToMetric()
+	}
+
+	c.HydrostaticRows = MetricTable(c.HydrostaticRows, c.HydrostaticUnit)
+	c.MTCRows = mtcRows
+	c.TPCListPort = TPIToTPC(c.TPCListPort)
+	c.TPCListStarboard = TPIToTPC(c.TPCListStarboard)
+	c.HydrostaticUnit = HydrostaticUnitMetric
+	return c
+}
@@ -1,37 +1,58 @@
 package types
 
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
 type OtherDeductibles struct {
-	Others     float64
-	OthersName string
+	Others     units.Mass `json:"others"`
+	OthersName string     `json:"others_name"`
 }
 
 type FreshWaterTank struct {
-	Name     string
-	Sounding float64
-	Volume   float64
+	Name     string       `json:"name"`
+	Sounding float64      `json:"sounding"`
+	Volume   units.Volume `json:"volume"`
 }
 
 func (fwt FreshWaterTank) GetWeight() float64 {
-	const density = 1.0
-	return fwt.Volume * density
+	const density units.Density = 1.0
+	return fwt.Volume.Mass(density).Tonnes()
 }
 
 type BallastWaterTank struct {
-	Name     string
-	Sounding float64
-	Volume   float64
-	Density  float64
+	Name     string        `json:"name"`
+	Sounding float64       `json:"sounding"`
+	Volume   units.Volume  `json:"volume"`
+	Density  units.Density `json:"density"`
 }
 
 func (bwt BallastWaterTank) GetWeight() float64 {
-	return bwt.Volume * bwt.Density
+	return bwt.Volume.Mass(bwt.Density).Tonnes()
+}
+
+type IceAccretion struct {
+	Zone      string        `json:"zone"`
+	Area      units.Area    `json:"area"`
+	Thickness units.Length  `json:"thickness"`
+	Density   units.Density `json:"density"`
+}
+
+func (ia IceAccretion) GetWeight() float64 {
+	const iceDensity units.Density = 0.9
+	density := ia.Density
+	if density == 0 {
+		density = iceDensity
+	}
+	return ia.Area.Volume(ia.Thickness).Mass(density).Tonnes()
 }
 
 type Deductibles struct {
-	HFO         float64
-	MDO         float64
-	LubOil      float64
-	BilgeWater  float64
-	SewageWater float64
+	HFO         units.Mass `json:"hfo"`
+	MDO         units.Mass `json:"mdo"`
+	LubOil      units.Mass `json:"lub_oil"`
+	BilgeWater  units.Mass `json:"bilge_water"`
+	SewageWater units.Mass `json:"sewage_water"`
 	OtherDeductibles
+	IceAccretion []IceAccretion `json:"ice_accretion"`
 }
@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+type ConstantRecord struct {
+	SurveyID string
+	Date     time.Time
+	Constant float64
+}
+
+type ConstantStats struct {
+	Count      int
+	Mean       float64
+	StdDev     float64
+	Min        float64
+	Max        float64
+	TrendPerYr float64
+}
+
+type ConstantThreshold struct {
+	MinSurveys int
+	Sigma      float64
+	Tonnes     float64
+}
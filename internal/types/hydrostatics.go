@@ -1,5 +1,9 @@
 package types
 
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
 type LCFDirection string
 
 const (
@@ -8,17 +12,69 @@ const (
 	LCFDirectionFromAP  LCFDirection = "AP"
 )
 
+type HydrostaticUnit string
+
+const (
+	HydrostaticUnitMetric   HydrostaticUnit = "metric"
+	HydrostaticUnitImperial HydrostaticUnit = "imperial"
+)
+
+// HydrostaticRow holds one line of the vessel's hydrostatic table in the units
+// of that table, metric or imperial according to the condition's
+// HydrostaticUnit, so its fields stay plain numbers until ToMetric.
 type HydrostaticRow struct {
-	Draft        float64
-	Displacement float64
-	TPC          float64
-	LCF          float64
-	LCFDirection LCFDirection
+	Draft        float64      `json:"draft"`
+	Displacement float64      `json:"displacement"`
+	TPC          float64      `json:"tpc"`
+	LCF          float64      `json:"lcf"`
+	LCFDirection LCFDirection `json:"lcf_direction"`
+}
+
+// ToMetric converts a row read from an imperial table: draft and LCF in feet,
+// displacement in long tons and TPI in place of TPC.
+func (r HydrostaticRow) ToMetric() HydrostaticRow {
+	return HydrostaticRow{
+		Draft:        units.Feet(r.Draft).Metres(),
+		Displacement: units.LongTons(r.Displacement).Tonnes(),
+		TPC:          TPIToTPC(r.TPC),
+		LCF:          units.Feet(r.LCF).Metres(),
+		LCFDirection: r.LCFDirection,
+	}
+}
+
+// MetricTable returns the rows of a hydrostatic table kept in unit, converted
+// to metric when the table is imperial.
+func MetricTable(rows []HydrostaticRow, unit HydrostaticUnit) []HydrostaticRow {
+	if unit != HydrostaticUnitImperial {
+		return rows
+	}
+	metric := make([]HydrostaticRow, len(rows))
+	for i, r := range rows {
+		metric[i] = r.ToMetric()
+	}
+	return metric
 }
 
 type MTCRow struct {
-	Draft float64
-	MTC   float64
+	Draft float64 `json:"draft"`
+	MTC   float64 `json:"mtc"`
+}
+
+// ToMetric converts a row read from an imperial table: draft in feet and
+// MTI (long ton-feet per inch) in place of MTC.
+func (r MTCRow) ToMetric() MTCRow {
+	return MTCRow{
+		Draft: units.Feet(r.Draft).Metres(),
+		MTC:   units.LongTons(r.MTC).Tonnes() * units.Feet(1).Metres() / units.Inches(1).Centimetres(),
+	}
+}
+
+func TPIToTPC(tpi float64) float64 {
+	return units.LongTons(tpi).Tonnes() / units.Inches(1).Centimetres()
+}
+
+func TPCToTPI(tpc float64) float64 {
+	return tpc * units.Inches(1).Centimetres() / units.LongTons(1).Tonnes()
 }
 
 type Hydrostatics struct {
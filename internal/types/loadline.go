@@ -0,0 +1,28 @@
+package types
+
+type AllowanceSource string
+
+const (
+	AllowanceSourceSummer      AllowanceSource = "summer"
+	AllowanceSourceHydrostatic AllowanceSource = "hydrostatic"
+)
+
+type AllowanceResult struct {
+	Source           AllowanceSource
+	Draft            float64
+	Displacement     float64
+	TPC              float64
+	DockDensity      float64
+	FWA              float64
+	DWA              float64
+	PermissibleDraft float64
+}
+
+type LoadLineResult struct {
+	Allowances  AllowanceResult
+	MMC         float64
+	DraftExcess float64
+	CurrentDWT  float64
+	DWTExcess   float64
+	Overloaded  bool
+}
@@ -1,5 +1,12 @@
 package types
 
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
 type ReadingMethod string
 
 const (
@@ -7,16 +14,46 @@ const (
 	ReadingMethodWaterline ReadingMethod = "waterline"
 )
 
+type DraftUnit string
+
+const (
+	DraftUnitMetre      DraftUnit = "m"
+	DraftUnitFeetInches DraftUnit = "ft-in"
+)
+
 type Mark struct {
-	Value  float64
-	Method ReadingMethod
+	Value  float64       `json:"value"`
+	Method ReadingMethod `json:"method"`
+	Unit   DraftUnit     `json:"unit"`
+	Feet   int           `json:"feet"`
+	Inches float64       `json:"inches"`
+}
+
+func (m Mark) Metres() float64 {
+	if m.Unit == DraftUnitFeetInches {
+		return (units.Feet(float64(m.Feet)) + units.Inches(m.Inches)).Metres()
+	}
+	return m.Value
+}
+
+func (m Mark) String() string {
+	if m.Unit == DraftUnitFeetInches {
+		return fmt.Sprintf("%d' %s\"", m.Feet, strconv.FormatFloat(m.Inches, 'f', -1, 64))
+	}
+	return strconv.FormatFloat(m.Value, 'f', 3, 64) + " m"
+}
+
+type MarkReading struct {
+	Name string `json:"name"`
+	Mark Mark   `json:"mark"`
 }
 
 type Marks struct {
-	FwdPort      Mark
-	FwdStarboard Mark
-	MidPort      Mark
-	MidStarboard Mark
-	AftPort      Mark
-	AftStarboard Mark
+	FwdPort      Mark          `json:"fwd_port"`
+	FwdStarboard Mark          `json:"fwd_starboard"`
+	MidPort      Mark          `json:"mid_port"`
+	MidStarboard Mark          `json:"mid_starboard"`
+	AftPort      Mark          `json:"aft_port"`
+	AftStarboard Mark          `json:"aft_starboard"`
+	Readings     []MarkReading `json:"readings"`
 }
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+type ApportionMethod string
+
+const (
+	ApportionProRata      ApportionMethod = "pro-rata"
+	ApportionIntermediate ApportionMethod = "intermediate"
+)
+
+type Parcel struct {
+	BLNumber     string     `json:"bl_number"`
+	Receiver     string     `json:"receiver"`
+	Declared     units.Mass `json:"declared"`
+	Intermediate units.Mass `json:"intermediate"`
+}
+
+type ParcelResult struct {
+	BLNumber   string
+	Receiver   string
+	Declared   float64
+	Quantity   float64
+	Difference float64
+}
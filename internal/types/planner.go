@@ -0,0 +1,25 @@
+package types
+
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+type LoadingPlan struct {
+	PlannedCargo      units.Mass
+	CargoOnBoard      units.Mass
+	DeductiblesChange units.Mass
+	Density           units.Density
+	Trim              units.Length
+	TargetDraft       units.Length
+}
+
+type LoadingPrediction struct {
+	CargoRemaining      float64
+	Displacement        float64
+	MMC                 float64
+	DraftFwd            float64
+	DraftMid            float64
+	DraftAft            float64
+	FirstTrimCorrection float64
+	CargoToTargetDraft  float64
+}
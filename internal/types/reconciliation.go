@@ -0,0 +1,55 @@
+package types
+
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
+type FigureSource string
+
+const (
+	FigureSourceBillOfLading FigureSource = "B/L"
+	FigureSourceShoreScale   FigureSource = "shore"
+	FigureSourceShipFigure   FigureSource = "ship"
+)
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationWithin   ReconciliationStatus = "within"
+	ReconciliationWarning  ReconciliationStatus = "warning"
+	ReconciliationExceeded ReconciliationStatus = "exceeded"
+)
+
+type CargoFigures struct {
+	BillOfLading units.Mass `json:"bill_of_lading"`
+	ShoreScale   units.Mass `json:"shore_scale"`
+}
+
+type Tolerance struct {
+	WarningPercent float64 `json:"warning_percent"`
+	LimitPercent   float64 `json:"limit_percent"`
+}
+
+type Tolerances struct {
+	Default     Tolerance            `json:"default"`
+	ByCargo     map[string]Tolerance `json:"by_cargo"`
+	ByPrincipal map[string]Tolerance `json:"by_principal"`
+}
+
+func (t Tolerances) For(cargo, principal string) Tolerance {
+	if tol, ok := t.ByPrincipal[principal]; ok {
+		return tol
+	}
+	if tol, ok := t.ByCargo[cargo]; ok {
+		return tol
+	}
+	return t.Default
+}
+
+type FigureDifference struct {
+	Source     FigureSource
+	Declared   float64
+	Difference float64
+	Percent    float64
+	Status     ReconciliationStatus
+}
@@ -0,0 +1,62 @@
+package types
+
+type ConditionResult struct {
+	MeanDraft            MeanDraft
+	PPCorrections        PPCorrections
+	DraftsWKeel          DraftsWKeel
+	MMC                  float64
+	Hydrostatics         Hydrostatics
+	FirstTrimCorrection  float64
+	SecondTrimCorrection float64
+	ListCorrection       float64
+	DensityCorrection    float64
+	DisplCorrToDensity   float64
+	TotalDeductibles     float64
+	NetDisplacement      float64
+}
+
+type ConstantResult struct {
+	Condition    ConditionResult
+	Constant     float64
+	Declared     float64
+	DeclaredDiff float64
+	History      ConstantStats
+	HistoryDiff  float64
+	Warnings     []Warning
+}
+
+type SurveyResult struct {
+	Initial              ConditionResult
+	Final                ConditionResult
+	Cargo                float64
+	Constant             float64
+	ConstantDeclaredDiff float64
+	Reconciliation       []FigureDifference
+	LoadLine             *LoadLineResult
+	Parcels              []ParcelResult
+	DeductiblesChange    DeductiblesChange
+	Warnings             []Warning
+}
+
+type ConvoyUnitResult struct {
+	Name    string
+	Initial ConditionResult
+	Final   ConditionResult
+	Cargo   float64
+}
+
+type ConvoyResult struct {
+	Units          []ConvoyUnitResult
+	TotalCargo     float64
+	Reconciliation []FigureDifference
+}
+
+type TransferResult struct {
+	Mother             SurveyResult
+	Daughter           SurveyResult
+	MotherCargo        float64
+	DaughterCargo      float64
+	Discrepancy        float64
+	DiscrepancyPercent float64
+	Status             ReconciliationStatus
+}
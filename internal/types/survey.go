@@ -3,6 +3,7 @@ package types
 import (
 	"time"
 
+	"github.com/AVZotov/draft-survey/internal/units"
 	"github.com/AVZotov/draft-survey/internal/vessel"
 )
 
@@ -25,59 +26,104 @@ type DraftsWKeel struct {
 }
 
 type InitialDraft struct {
-	BallastWaterTanks []BallastWaterTank
-	FreshWaterTanks   []FreshWaterTank
-	Deductibles       Deductibles
-	Marks             Marks
-	ConstantDeclared  float64
-	Density           float64
-	StartedAt         time.Time
-	FinishedAt        time.Time
-	MTCRows           []MTCRow
-	HydrostaticRows   []HydrostaticRow
-	TPCListPort       float64
-	TPCListStarboard  float64
-	SeaCondition      SeaCondition
+	BallastWaterTanks []BallastWaterTank `json:"ballast_water_tanks"`
+	FreshWaterTanks   []FreshWaterTank   `json:"fresh_water_tanks"`
+	Deductibles       Deductibles        `json:"deductibles"`
+	Marks             Marks              `json:"marks"`
+	ConstantDeclared  units.Mass         `json:"constant_declared"`
+	Density           units.Density      `json:"density"`
+	StartedAt         time.Time          `json:"started_at"`
+	FinishedAt        time.Time          `json:"finished_at"`
+	MTCRows           []MTCRow           `json:"mtc_rows"`
+	HydrostaticRows   []HydrostaticRow   `json:"hydrostatic_rows"`
+	HydrostaticUnit   HydrostaticUnit    `json:"hydrostatic_unit"`
+	TPCListPort       float64            `json:"tpc_list_port"`
+	TPCListStarboard  float64            `json:"tpc_list_starboard"`
+	SeaCondition      SeaCondition       `json:"sea_condition"`
 }
 
 type FinalDraft struct {
-	BallastWaterTanks []BallastWaterTank
-	FreshWaterTanks   []FreshWaterTank
-	Deductibles       Deductibles
-	Marks             Marks
-	CargoDeclared     float64
-	Density           float64
-	StartedAt         time.Time
-	FinishedAt        time.Time
-	MTCRows           []MTCRow
-	HydrostaticRows   []HydrostaticRow
-	TPCListPort       float64
-	TPCListStarboard  float64
-	SeaCondition      SeaCondition
+	BallastWaterTanks []BallastWaterTank `json:"ballast_water_tanks"`
+	FreshWaterTanks   []FreshWaterTank   `json:"fresh_water_tanks"`
+	Deductibles       Deductibles        `json:"deductibles"`
+	Marks             Marks              `json:"marks"`
+	CargoDeclared     units.Mass         `json:"cargo_declared"`
+	Density           units.Density      `json:"density"`
+	StartedAt         time.Time          `json:"started_at"`
+	FinishedAt        time.Time          `json:"finished_at"`
+	MTCRows           []MTCRow           `json:"mtc_rows"`
+	HydrostaticRows   []HydrostaticRow   `json:"hydrostatic_rows"`
+	HydrostaticUnit   HydrostaticUnit    `json:"hydrostatic_unit"`
+	TPCListPort       float64            `json:"tpc_list_port"`
+	TPCListStarboard  float64            `json:"tpc_list_starboard"`
+	SeaCondition      SeaCondition       `json:"sea_condition"`
+}
+
+type SurveyKind string
+
+const (
+	SurveyKindDraft    SurveyKind = "draft"
+	SurveyKindConstant SurveyKind = "constant"
+	SurveyKindConvoy   SurveyKind = "convoy"
+	SurveyKindSTS      SurveyKind = "sts"
+)
+
+type SurveyStatus string
+
+const (
+	SurveyStatusOpen      SurveyStatus = "open"
+	SurveyStatusCompleted SurveyStatus = "completed"
+	SurveyStatusReported  SurveyStatus = "reported"
+)
+
+type STSRole string
+
+const (
+	STSRoleMother   STSRole = "mother"
+	STSRoleDaughter STSRole = "daughter"
+)
+
+type STSLink struct {
+	Role          STSRole `json:"role"`
+	CounterpartID string  `json:"counterpart_id"`
 }
 
 type Job struct {
-	JobNumber int
-	DSNumber  int
-	Principal string
+	JobNumber int          `json:"job_number"`
+	DSNumber  int          `json:"ds_number"`
+	Principal string       `json:"principal"`
+	Units     units.System `json:"units"`
 }
 
 type CargoOperation struct {
-	PlaceOfInspection string
-	Destination       string
-	Operation         string
-	Origin            string
-	Cargo             string
-	Packing           string
-	Port              string
+	PlaceOfInspection string          `json:"place_of_inspection"`
+	Destination       string          `json:"destination"`
+	Operation         string          `json:"operation"`
+	Origin            string          `json:"origin"`
+	Cargo             string          `json:"cargo"`
+	Packing           string          `json:"packing"`
+	Port              string          `json:"port"`
+	Figures           CargoFigures    `json:"figures"`
+	Parcels           []Parcel        `json:"parcels"`
+	ApportionMethod   ApportionMethod `json:"apportion_method"`
+}
+
+type ConvoyUnit struct {
+	VesselData   vessel.VesselData `json:"vessel_data"`
+	InitialDraft InitialDraft      `json:"initial_draft"`
+	FinalDraft   FinalDraft        `json:"final_draft"`
 }
 
 type Survey struct {
-	Surveyor       *User
-	ID             string
-	InitialDraft   InitialDraft
-	FinalDraft     FinalDraft
-	Job            Job
-	CargoOperation CargoOperation
-	VesselData     vessel.VesselData
+	Surveyor       *User             `json:"surveyor"`
+	ID             string            `json:"id"`
+	Kind           SurveyKind        `json:"kind"`
+	Status         SurveyStatus      `json:"status"`
+	InitialDraft   InitialDraft      `json:"initial_draft"`
+	FinalDraft     FinalDraft        `json:"final_draft"`
+	Job            Job               `json:"job"`
+	CargoOperation CargoOperation    `json:"cargo_operation"`
+	VesselData     vessel.VesselData `json:"vessel_data"`
+	Convoy         []ConvoyUnit      `json:"convoy"`
+	STS            *STSLink          `json:"sts"`
 }
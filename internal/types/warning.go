@@ -0,0 +1,15 @@
+package types
+
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+type Warning struct {
+	Code     string
+	Severity Severity
+	Message  string
+}
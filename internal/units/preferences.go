@@ -0,0 +1,62 @@
+package units
+
+import (
+	"strconv"
+)
+
+type System string
+
+const (
+	SystemMetric   System = "metric"
+	SystemImperial System = "imperial"
+)
+
+type Preferences struct {
+	Length  LengthUnit
+	Mass    MassUnit
+	Density DensityUnit
+	Volume  VolumeUnit
+}
+
+func PreferencesFor(s System) Preferences {
+	if s == SystemImperial {
+		return Preferences{Length: Foot, Mass: LongTon, Density: TonnePerCubicMetre, Volume: CubicFoot}
+	}
+	return Preferences{Length: Metre, Mass: Tonne, Density: TonnePerCubicMetre, Volume: CubicMetre}
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+func (p Preferences) FormatLength(l Length) string {
+	v, err := l.In(p.Length)
+	if err != nil {
+		return format(l.Metres())
+	}
+	return format(v)
+}
+
+func (p Preferences) FormatMass(m Mass) string {
+	v, err := m.In(p.Mass)
+	if err != nil {
+		return format(m.Tonnes())
+	}
+	return format(v)
+}
+
+func (p Preferences) FormatDensity(d Density) string {
+	v, err := d.In(p.Density)
+	if err != nil {
+		return format(d.TonnesPerCubicMetre())
+	}
+	return format(v)
+}
+
+func (p Preferences) FormatVolume(v Volume) string {
+	f, err := v.In(p.Volume)
+	if err != nil {
+		return format(v.CubicMetres())
+	}
+	return format(f)
+}
@@ -0,0 +1,263 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnknownUnit = errors.New("units: unknown unit")
+	ErrParse       = errors.New("units: cannot parse quantity")
+	ErrOutOfRange  = errors.New("units: value is out of plausible range")
+)
+
+type LengthUnit string
+
+const (
+	Metre      LengthUnit = "m"
+	Centimetre LengthUnit = "cm"
+	Millimetre LengthUnit = "mm"
+	Foot       LengthUnit = "ft"
+	Inch       LengthUnit = "in"
+)
+
+type MassUnit string
+
+const (
+	Tonne    MassUnit = "MT"
+	Kilogram MassUnit = "kg"
+	LongTon  MassUnit = "LT"
+	ShortTon MassUnit = "ST"
+)
+
+type DensityUnit string
+
+const (
+	TonnePerCubicMetre    DensityUnit = "t/m3"
+	KilogramPerCubicMetre DensityUnit = "kg/m3"
+)
+
+type VolumeUnit string
+
+const (
+	CubicMetre VolumeUnit = "m3"
+	Litre      VolumeUnit = "l"
+	CubicFoot  VolumeUnit = "ft3"
+	Barrel     VolumeUnit = "bbl"
+)
+
+var lengthFactors = map[LengthUnit]float64{
+	Metre:      1,
+	Centimetre: 0.01,
+	Millimetre: 0.001,
+	Foot:       0.3048,
+	Inch:       0.0254,
+}
+
+var massFactors = map[MassUnit]float64{
+	Tonne:    1,
+	Kilogram: 0.001,
+	LongTon:  1.0160469088,
+	ShortTon: 0.90718474,
+}
+
+var densityFactors = map[DensityUnit]float64{
+	TonnePerCubicMetre:    1,
+	KilogramPerCubicMetre: 0.001,
+}
+
+var volumeFactors = map[VolumeUnit]float64{
+	CubicMetre: 1,
+	Litre:      0.001,
+	CubicFoot:  0.028316846592,
+	Barrel:     0.158987294928,
+}
+
+// Length is stored in metres.
+type Length float64
+
+func NewLength(v float64, u LengthUnit) (Length, error) {
+	f, ok := lengthFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return Length(v * f), nil
+}
+
+func Metres(v float64) Length      { return Length(v) }
+func Millimetres(v float64) Length { return Length(v * lengthFactors[Millimetre]) }
+func Feet(v float64) Length        { return Length(v * lengthFactors[Foot]) }
+func Inches(v float64) Length      { return Length(v * lengthFactors[Inch]) }
+
+func (l Length) Metres() float64      { return float64(l) }
+func (l Length) Centimetres() float64 { return float64(l) / lengthFactors[Centimetre] }
+func (l Length) Millimetres() float64 { return float64(l) / lengthFactors[Millimetre] }
+
+func (l Length) In(u LengthUnit) (float64, error) {
+	f, ok := lengthFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return float64(l) / f, nil
+}
+
+// LengthMM is a length entered and stored in millimetres, such as keel
+// thickness. It has to be converted with Length before it can be combined with
+// lengths in metres.
+type LengthMM float64
+
+func (l LengthMM) Length() Length { return Millimetres(float64(l)) }
+
+// Mass is stored in metric tonnes.
+type Mass float64
+
+func NewMass(v float64, u MassUnit) (Mass, error) {
+	f, ok := massFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return Mass(v * f), nil
+}
+
+func Tonnes(v float64) Mass   { return Mass(v) }
+func LongTons(v float64) Mass { return Mass(v * massFactors[LongTon]) }
+
+func (m Mass) Tonnes() float64 { return float64(m) }
+
+func (m Mass) In(u MassUnit) (float64, error) {
+	f, ok := massFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return float64(m) / f, nil
+}
+
+// Density is stored in t/m³.
+type Density float64
+
+func NewDensity(v float64, u DensityUnit) (Density, error) {
+	f, ok := densityFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return Density(v * f), nil
+}
+
+func (d Density) TonnesPerCubicMetre() float64 { return float64(d) }
+
+func (d Density) In(u DensityUnit) (float64, error) {
+	f, ok := densityFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return float64(d) / f, nil
+}
+
+// Area is stored in m².
+type Area float64
+
+func (a Area) SquareMetres() float64 { return float64(a) }
+
+// Volume is the volume of a layer of the given thickness over the area.
+func (a Area) Volume(thickness Length) Volume {
+	return Volume(float64(a) * thickness.Metres())
+}
+
+// Volume is stored in m³.
+type Volume float64
+
+func NewVolume(v float64, u VolumeUnit) (Volume, error) {
+	f, ok := volumeFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return Volume(v * f), nil
+}
+
+func (v Volume) CubicMetres() float64 { return float64(v) }
+
+func (v Volume) In(u VolumeUnit) (float64, error) {
+	f, ok := volumeFactors[u]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, u)
+	}
+	return float64(v) / f, nil
+}
+
+func (v Volume) Mass(d Density) Mass {
+	return Mass(float64(v) * float64(d))
+}
+
+func splitQuantity(s string) (float64, string, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s[:i]), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %q", ErrParse, s)
+	}
+	return v, strings.TrimSpace(s[i:]), nil
+}
+
+func ParseLength(s string, def LengthUnit) (Length, error) {
+	v, u, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	if u == "" {
+		u = string(def)
+	}
+	return NewLength(v, LengthUnit(u))
+}
+
+func ParseMass(s string, def MassUnit) (Mass, error) {
+	v, u, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	if u == "" {
+		u = string(def)
+	}
+	return NewMass(v, MassUnit(u))
+}
+
+func ParseDensity(s string, def DensityUnit) (Density, error) {
+	v, u, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	if u == "" {
+		u = string(def)
+	}
+	d, err := NewDensity(v, DensityUnit(u))
+	if err != nil {
+		return 0, err
+	}
+	return d, ValidateWaterDensity(d)
+}
+
+func ParseVolume(s string, def VolumeUnit) (Volume, error) {
+	v, u, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	if u == "" {
+		u = string(def)
+	}
+	return NewVolume(v, VolumeUnit(u))
+}
+
+// ValidateWaterDensity catches kg/m³ figures entered as t/m³ and vice versa.
+func ValidateWaterDensity(d Density) error {
+	if d < 0.95 || d > 1.1 {
+		return fmt.Errorf("%w: water density %g t/m3", ErrOutOfRange, float64(d))
+	}
+	return nil
+}
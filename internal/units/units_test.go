@@ -0,0 +1,104 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLength_Conversion(t *testing.T) {
+	l := Feet(10) + Inches(6)
+	if !almostEqual(l.Metres(), 3.2004) {
+		t.Errorf("Expected 3.2004, got %f", l.Metres())
+	}
+	in, err := l.In(Inch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(in, 126) {
+		t.Errorf("Expected 126, got %f", in)
+	}
+	if !almostEqual(Millimetres(25).Metres(), 0.025) {
+		t.Errorf("Expected 0.025, got %f", Millimetres(25).Metres())
+	}
+	if !almostEqual(LengthMM(18).Length().Metres(), 0.018) {
+		t.Errorf("Expected 0.018, got %f", LengthMM(18).Length().Metres())
+	}
+}
+
+func TestMass_Conversion(t *testing.T) {
+	m := LongTons(1000)
+	if !almostEqual(m.Tonnes(), 1016.0469088) {
+		t.Errorf("Expected 1016.0469088, got %f", m.Tonnes())
+	}
+	if _, err := m.In(MassUnit("stone")); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("Expected %v, got %v", ErrUnknownUnit, err)
+	}
+}
+
+func TestArea_Volume(t *testing.T) {
+	if got := Area(120).Volume(Millimetres(50)).CubicMetres(); !almostEqual(got, 6) {
+		t.Errorf("Expected 6, got %f", got)
+	}
+}
+
+func TestVolume_Mass(t *testing.T) {
+	v, err := NewVolume(1000, CubicMetre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Mass(Density(1.025)).Tonnes(); !almostEqual(got, 1025) {
+		t.Errorf("Expected 1025, got %f", got)
+	}
+}
+
+func TestParseLength(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected float64
+	}{
+		{"25 mm", 0.025},
+		{"3.41m", 3.41},
+		{"3.41", 3.41},
+		{"11 ft", 3.3528},
+	}
+	for _, tt := range tests {
+		got, err := ParseLength(tt.in, Metre)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(got.Metres(), tt.expected) {
+			t.Errorf("%s: expected %f, got %f", tt.in, tt.expected, got.Metres())
+		}
+	}
+	if _, err := ParseLength("abc", Metre); !errors.Is(err, ErrParse) {
+		t.Errorf("Expected %v, got %v", ErrParse, err)
+	}
+}
+
+func TestParseDensity(t *testing.T) {
+	got, err := ParseDensity("1018 kg/m3", TonnePerCubicMetre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(got.TonnesPerCubicMetre(), 1.018) {
+		t.Errorf("Expected 1.018, got %f", got.TonnesPerCubicMetre())
+	}
+	if _, err := ParseDensity("1018", TonnePerCubicMetre); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected %v, got %v", ErrOutOfRange, err)
+	}
+}
+
+func TestPreferences_Format(t *testing.T) {
+	p := PreferencesFor(SystemImperial)
+	if got := p.FormatMass(Tonnes(1016.0469088)); got != "1000.000" {
+		t.Errorf("Expected 1000.000, got %s", got)
+	}
+	if got := p.FormatLength(Metres(3.048)); got != "10.000" {
+		t.Errorf("Expected 10.000, got %s", got)
+	}
+}
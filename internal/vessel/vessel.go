@@ -1,5 +1,9 @@
 package vessel
 
+import (
+	"github.com/AVZotov/draft-survey/internal/units"
+)
+
 type VesselType string
 
 const (
@@ -22,31 +26,68 @@ const (
 	PPDirectionAft     PPDirection = "A"
 )
 
+type MarkStation string
+
+const (
+	MarkStationFwd MarkStation = "FWD"
+	MarkStationMid MarkStation = "MID"
+	MarkStationAft MarkStation = "AFT"
+)
+
+type MarkSide string
+
+const (
+	MarkSidePort      MarkSide = "P"
+	MarkSideStarboard MarkSide = "S"
+)
+
+type SingleSideRule string
+
+const (
+	SingleSideRuleReject        SingleSideRule = "reject"
+	SingleSideRuleAccept        SingleSideRule = "accept"
+	SingleSideRuleListCorrected SingleSideRule = "list"
+)
+
+type DraftMark struct {
+	Name      string       `json:"name"`
+	Station   MarkStation  `json:"station"`
+	Side      MarkSide     `json:"side"`
+	Distance  units.Length `json:"distance"`
+	Direction PPDirection  `json:"direction"`
+}
+
 type VesselData struct {
-	Name                 string
-	Flag                 string
-	HomePort             string
-	IMO                  string
-	BuiltCountry         string
-	BuiltYear            int
-	HydrostaticDocsPhoto string
-	Lightship            float64 // вес порожнем, MT
-	Breadth              float64 // ширина корпуса, м
-	Depth                float64 // высота корпуса, м
-	LBP                  float64 // длина между перпендикулярами, м
-	SummerDraft          float64
-	SummerDWT            float64
-	SummerTPC            float64
-	SummerFreeboard      float64
-	DistancePPFwd        float64
-	PPFwdDirection       PPDirection
-	DistancePPMid        float64
-	PPMidDirection       PPDirection
-	DistancePPAft        float64
-	PPAftDirection       PPDirection
-	KeelFwd              float64
-	KeelMid              float64
-	KeelAft              float64
-	VesselType           VesselType
-	CorrectionMethod     CorrectionMethod
+	Name                 string           `json:"name"`
+	Flag                 string           `json:"flag"`
+	HomePort             string           `json:"home_port"`
+	IMO                  string           `json:"imo"`
+	BuiltCountry         string           `json:"built_country"`
+	BuiltYear            int              `json:"built_year"`
+	HydrostaticDocsPhoto string           `json:"hydrostatic_docs_photo"`
+	Lightship            units.Mass       `json:"lightship"` // вес порожнем, MT
+	Breadth              units.Length     `json:"breadth"`   // ширина корпуса, м
+	Depth                units.Length     `json:"depth"`     // высота корпуса, м
+	LBP                  units.Length     `json:"lbp"`       // длина между перпендикулярами, м
+	SummerDraft          units.Length     `json:"summer_draft"`
+	SummerDWT            units.Mass       `json:"summer_dwt"`
+	SummerTPC            float64          `json:"summer_tpc"`
+	SummerFreeboard      units.Length     `json:"summer_freeboard"`
+	DistancePPFwd        units.Length     `json:"distance_pp_fwd"`
+	PPFwdDirection       PPDirection      `json:"pp_fwd_direction"`
+	DistancePPMid        units.Length     `json:"distance_pp_mid"`
+	PPMidDirection       PPDirection      `json:"pp_mid_direction"`
+	DistancePPAft        units.Length     `json:"distance_pp_aft"`
+	PPAftDirection       PPDirection      `json:"pp_aft_direction"`
+	KeelFwd              units.LengthMM   `json:"keel_fwd"`
+	KeelMid              units.LengthMM   `json:"keel_mid"`
+	KeelAft              units.LengthMM   `json:"keel_aft"`
+	VesselType           VesselType       `json:"vessel_type"`
+	CorrectionMethod     CorrectionMethod `json:"correction_method"`
+	MarkLayout           []DraftMark      `json:"mark_layout"`
+	SingleSideRule       SingleSideRule   `json:"single_side_rule"`
+}
+
+func (v VesselData) KeelThickness() (fwd, mid, aft units.Length) {
+	return v.KeelFwd.Length(), v.KeelMid.Length(), v.KeelAft.Length()
 }